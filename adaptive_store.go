@@ -0,0 +1,292 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// Kinds of adaptiveNode, chosen per node by the number and spread of the
+// bytes which can follow it
+const (
+	adaptiveLeaf  = iota // no next bytes
+	adaptiveList         // children for up to adaptiveListMax next bytes, searched by label
+	adaptiveIndex        // children for up to adaptiveIndexMax next bytes, found by a 256 entry table
+	adaptiveRange        // child for each byte in a range, as in Uint32Store
+
+	adaptiveKindMask = 3
+	adaptiveValid    = 4 // flag for the byte sequence with no more bytes being in the map
+)
+
+const (
+	adaptiveListMax     = 16
+	adaptiveIndexMax    = 48
+	adaptiveRangeSpread = 2 // range nodes are used if at least 1/adaptiveRangeSpread of the range has children
+
+	adaptiveNodeSize  = int(unsafe.Sizeof(adaptiveNode{}))
+	adaptiveTableSize = 4 + 256 // index of the first child then one more than the position of the child for each byte, 0 for none
+)
+
+type (
+	// AdaptiveUint32Store is a fast read only map from string to uint32
+	// like Uint32Store except that each node has one of several forms, as
+	// in an adaptive radix tree, chosen at build time by how many bytes can
+	// follow it and how spread out they are. A node with a few widely
+	// spread next bytes, say "!" and "~", holds just those bytes rather
+	// than a range of 94 slots, while dense nodes still index a range.
+	// The zero value is an empty map.
+	AdaptiveUint32Store struct {
+		nodes  []adaptiveNode
+		tables []byte // adaptiveTableSize bytes for each index node
+	}
+
+	// adaptiveNode is a node of the trie. It is the same size as a byteValue
+	// so that a range node costs no more than in Uint32Store.
+	adaptiveNode struct {
+		value uint32 // value for byte sequence with no more bytes
+		next  uint32 // index in nodes of the first child, or in tables of the table of index nodes
+		last  byte   // number of children, or length of the range, minus one
+		lo    byte   // lowest next byte of range nodes
+		label byte   // byte leading to the node from its parent
+		flags byte   // kind and adaptiveValid
+	}
+
+	// nodeTask is a node still to be set from store index i of a Uint32Store
+	nodeTask struct {
+		id, i uint32
+	}
+)
+
+// NewAdaptiveUint32Store creates from the data supplied in src
+func NewAdaptiveUint32Store(src Uint32Source) AdaptiveUint32Store {
+	m := NewUint32Store(src)
+
+	// count the nodes and tables first so that they are allocated exactly
+	nNodes, nTables := 1, 0
+	var next []byte
+	stack := []uint32{m.root}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		var kind byte
+		var n int
+		next, kind, n = adaptiveShape(&m, i, next[:0])
+		nNodes += n
+		if kind == adaptiveIndex {
+			nTables += adaptiveTableSize
+		}
+		bv := &m.store[i]
+		for _, b := range next {
+			stack = append(stack, bv.nextLo+uint32(b-bv.nextOffset))
+		}
+	}
+
+	a := AdaptiveUint32Store{nodes: make([]adaptiveNode, nNodes), tables: make([]byte, nTables)}
+	usedNodes, usedTables := uint32(1), uint32(0)
+	tasks := []nodeTask{{id: 0, i: m.root}}
+	for len(tasks) > 0 {
+		t := tasks[len(tasks)-1]
+		tasks = tasks[:len(tasks)-1]
+		var kind byte
+		var n int
+		next, kind, n = adaptiveShape(&m, t.i, next[:0])
+		bv, nd := &m.store[t.i], &a.nodes[t.id]
+		nd.value = bv.value
+		if bv.valid {
+			nd.flags = adaptiveValid
+		}
+		if kind == adaptiveLeaf {
+			continue
+		}
+		nd.flags |= kind
+		first := usedNodes
+		usedNodes += uint32(n)
+		nd.next, nd.last = first, byte(n-1)
+		switch kind {
+		case adaptiveRange:
+			nd.lo = next[0]
+		case adaptiveIndex:
+			nd.next = usedTables
+			table := a.tables[usedTables : usedTables+adaptiveTableSize]
+			usedTables += adaptiveTableSize
+			binary.LittleEndian.PutUint32(table, first)
+			for j, b := range next {
+				table[4+int(b)] = byte(j + 1)
+			}
+		}
+		for j, b := range next {
+			c := first + uint32(j)
+			if kind == adaptiveRange {
+				c = first + uint32(b-next[0])
+			}
+			a.nodes[c].label = b
+			tasks = append(tasks, nodeTask{id: c, i: bv.nextLo + uint32(b-bv.nextOffset)})
+		}
+	}
+	return a
+}
+
+// adaptiveShape appends to next the bytes following store index i of m
+// which lead to keys, returning it with the kind of node for i and the
+// number of nodes needed for its children
+func adaptiveShape(m *Uint32Store, i uint32, next []byte) ([]byte, byte, int) {
+	bv := &m.store[i]
+	for j := uint32(0); j < uint32(bv.nextLen); j++ {
+		if c := &m.store[bv.nextLo+j]; c.valid || c.nextLen > 0 {
+			next = append(next, bv.nextOffset+byte(j))
+		}
+	}
+	if len(next) == 0 {
+		return next, adaptiveLeaf, 0
+	}
+	span := int(next[len(next)-1]-next[0]) + 1
+	switch {
+	case span <= adaptiveRangeSpread*len(next) || len(next) > adaptiveIndexMax:
+		return next, adaptiveRange, span
+	case len(next) <= adaptiveListMax:
+		return next, adaptiveList, len(next)
+	case (span-len(next))*adaptiveNodeSize > adaptiveTableSize:
+		return next, adaptiveIndex, len(next)
+	}
+	// the table would take more memory than the unused slots of a range
+	return next, adaptiveRange, span
+}
+
+// children returns the index in nodes of the first child of nd, and the
+// number of children, which are consecutive nodes in order of their labels
+func (a *AdaptiveUint32Store) children(nd *adaptiveNode) (uint32, int) {
+	switch nd.flags & adaptiveKindMask {
+	case adaptiveLeaf:
+		return 0, 0
+	case adaptiveIndex:
+		return binary.LittleEndian.Uint32(a.tables[nd.next:]), int(nd.last) + 1
+	}
+	return nd.next, int(nd.last) + 1
+}
+
+// child returns the index of the child of nodes[i] for byte b
+func (a *AdaptiveUint32Store) child(i uint32, b byte) (uint32, bool) {
+	nd := &a.nodes[i]
+	switch nd.flags & adaptiveKindMask {
+	case adaptiveList:
+		for c, end := nd.next, nd.next+uint32(nd.last); c <= end; c++ {
+			if l := a.nodes[c].label; l >= b {
+				return c, l == b
+			}
+		}
+	case adaptiveIndex:
+		table := a.tables[nd.next : nd.next+adaptiveTableSize]
+		if j := table[4+int(b)]; j > 0 {
+			return binary.LittleEndian.Uint32(table) + uint32(j) - 1, true
+		}
+	case adaptiveRange:
+		if j := uint16(b) - uint16(nd.lo); j <= uint16(nd.last) {
+			return nd.next + uint32(j), true
+		}
+	}
+	return 0, false
+}
+
+// LookupString looks up the supplied string in the map
+func (a *AdaptiveUint32Store) LookupString(s string) (uint32, bool) {
+	if len(a.nodes) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	for j := 0; j < len(s); j++ {
+		// range nodes inline as they are the most common
+		if nd := &a.nodes[i]; nd.flags&adaptiveKindMask == adaptiveRange {
+			k := uint16(s[j]) - uint16(nd.lo)
+			if k > uint16(nd.last) {
+				return 0, false
+			}
+			i = nd.next + uint32(k)
+			continue
+		}
+		var ok bool
+		if i, ok = a.child(i, s[j]); !ok {
+			return 0, false
+		}
+	}
+	nd := &a.nodes[i]
+	return nd.value, nd.flags&adaptiveValid != 0
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (a *AdaptiveUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	if len(a.nodes) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	for _, b := range s {
+		// range nodes inline as they are the most common
+		if nd := &a.nodes[i]; nd.flags&adaptiveKindMask == adaptiveRange {
+			k := uint16(b) - uint16(nd.lo)
+			if k > uint16(nd.last) {
+				return 0, false
+			}
+			i = nd.next + uint32(k)
+			continue
+		}
+		var ok bool
+		if i, ok = a.child(i, b); !ok {
+			return 0, false
+		}
+	}
+	nd := &a.nodes[i]
+	return nd.value, nd.flags&adaptiveValid != 0
+}
+
+// Len returns the number of keys in the map
+func (a *AdaptiveUint32Store) Len() int {
+	n := 0
+	for i := range a.nodes {
+		if a.nodes[i].flags&adaptiveValid != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Walk calls fn for each key in the map in sorted order until fn returns
+// false. It uses an explicit stack, like Uint32Store.Walk, so that the
+// length of the keys does not bound the depth of the Go stack.
+func (a *AdaptiveUint32Store) Walk(fn func(string, uint32) bool) {
+	if len(a.nodes) == 0 {
+		return
+	}
+	var frames [walkStackSize]iterFrame
+	stack := append(frames[:0], iterFrame{node: 0, next: -1})
+	key := make([]byte, 0, 256)
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		nd := &a.nodes[f.node]
+		first, n := a.children(nd)
+		switch {
+		case f.next < 0:
+			f.next = 0
+			if nd.flags&adaptiveValid != 0 && !fn(string(key), nd.value) {
+				return
+			}
+		case f.next < n:
+			c := first + uint32(f.next)
+			f.next++
+			if a.nodes[c].flags == 0 {
+				continue // unused slot in a range
+			}
+			stack = append(stack, iterFrame{node: c, next: -1})
+			key = append(key, a.nodes[c].label)
+		default:
+			if stack = stack[:len(stack)-1]; len(stack) > 0 {
+				key = key[:len(key)-1]
+			}
+		}
+	}
+}
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the AdaptiveUint32Store value itself
+func (a *AdaptiveUint32Store) SizeInBytes() int {
+	return cap(a.nodes)*adaptiveNodeSize + cap(a.tables)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestAdaptiveUint32Store(t *testing.T) {
+	m := randomSmallStrings(20000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	a := faststringmap.NewAdaptiveUint32Store(ms)
+	checkLookuper(t, "random", &a, ms)
+
+	ms = typicalCodeStrings(1000)
+	ms.out = []string{"", "0", "1000", "-", "-8", "9a"}
+	a = faststringmap.NewAdaptiveUint32Store(ms)
+	checkLookuper(t, "codes", &a, ms)
+
+	// two children spread across the printable bytes
+	ms = mapSliceN(map[string]uint32{"a!": 1, "a~": 2}, 2)
+	ms.out = []string{"a", "a\"", "a}", "b!"}
+	a = faststringmap.NewAdaptiveUint32Store(ms)
+	checkLookuper(t, "sparse", &a, ms)
+	fm := faststringmap.NewUint32Store(ms)
+	if a.SizeInBytes()*4 > fm.SizeInBytes() {
+		t.Errorf("adaptive %d bytes not much smaller than %d", a.SizeInBytes(), fm.SizeInBytes())
+	}
+
+	// a node with between 17 and 48 widely spread next bytes
+	mid := map[string]uint32{}
+	for i := 0; i < 30; i++ {
+		mid[string([]byte{byte(i * 8)})] = uint32(i)
+	}
+	ms = mapSliceN(mid, 30)
+	ms.out = []string{"\x01", "\xff", "\x08\x00"}
+	a = faststringmap.NewAdaptiveUint32Store(ms)
+	checkLookuper(t, "index", &a, ms)
+
+	var zero faststringmap.AdaptiveUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	a = faststringmap.NewAdaptiveUint32Store(mapSlice{})
+	checkLookuper(t, "empty", &a, mapSlice{out: []string{"", "a"}})
+}
+
+func TestAdaptiveUint32StoreSize(t *testing.T) {
+	// path-like keys have sparse nodes after each "/" and in the words
+	dirs := []string{"bin", "etc", "home", "lib", "opt", "srv", "usr", "var"}
+	paths := map[string]uint32{}
+	for i := 0; i < 5000; i++ {
+		k := fmt.Sprintf("/%s/%s/%s%d.go", dirs[i%8], dirs[i/8%8], dirs[i/64%8], i)
+		paths[k] = uint32(i)
+	}
+	ms := mapSliceN(paths, len(paths))
+	a := faststringmap.NewAdaptiveUint32Store(ms)
+	checkLookuper(t, "paths", &a, ms)
+	fm := faststringmap.NewUint32Store(ms)
+	if a.SizeInBytes()*10 > fm.SizeInBytes()*9 {
+		t.Errorf("paths: adaptive %d bytes not much smaller than %d", a.SizeInBytes(), fm.SizeInBytes())
+	}
+
+	// digits make dense ranges, which are no larger than in Uint32Store
+	ms = typicalCodeStrings(5000)
+	a = faststringmap.NewAdaptiveUint32Store(ms)
+	fm = faststringmap.NewUint32Store(ms)
+	if a.SizeInBytes() > fm.SizeInBytes() {
+		t.Errorf("codes: adaptive %d bytes larger than %d", a.SizeInBytes(), fm.SizeInBytes())
+	}
+
+	// long keys do not make a deep Go stack
+	long := mapSliceN(map[string]uint32{strings.Repeat("a!", 20000): 1, strings.Repeat("a~", 20000): 2}, 2)
+	a = faststringmap.NewAdaptiveUint32Store(long)
+	checkLookuper(t, "long", &a, long)
+}
+
+func BenchmarkAdaptiveUint32Store(b *testing.B) {
+	m := typicalCodeStrings(nStrsBench)
+	a := faststringmap.NewAdaptiveUint32Store(m)
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := a.LookupString(m.in[si])
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
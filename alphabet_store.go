@@ -0,0 +1,150 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNotInAlphabet is returned (wrapped) when a key has a byte outside the alphabet
+var ErrNotInAlphabet = errors.New("faststringmap: byte not in alphabet")
+
+// AlphabetUint32Store is a fast read only map from string to uint32 for
+// keys using a small alphabet, such as hex digits or base32. The bytes of
+// the alphabet are numbered in order from 1 and the trie is built from the
+// numbers, so the ranges of next bytes in the trie are at most the size of
+// the alphabet, which makes the map much smaller when the alphabet is
+// spread out, as for "0-9a-f". Strings with bytes outside the alphabet are
+// rejected on the first such byte.
+// The zero value is an empty map.
+type AlphabetUint32Store struct {
+	m      Uint32Store // keys with each byte replaced by its code
+	code   [256]byte   // code for each byte, or 0 if not in the alphabet
+	decode []byte      // byte for each code
+}
+
+// NewAlphabetUint32Store creates from the data supplied in src, whose keys
+// must only use the bytes in alphabet, in any order. The error wraps
+// ErrNotInAlphabet if a key uses another byte or alphabet has all 256 bytes.
+func NewAlphabetUint32Store(src Uint32Source, alphabet string) (AlphabetUint32Store, error) {
+	var in [256]bool
+	for i := 0; i < len(alphabet); i++ {
+		in[alphabet[i]] = true
+	}
+	return newAlphabetUint32Store(src, &in)
+}
+
+// NewAlphabetUint32StoreAuto creates from the data supplied in src using
+// the bytes which occur in its keys as the alphabet. The error wraps
+// ErrNotInAlphabet if the keys use all 256 bytes, as one is needed to mark
+// bytes outside the alphabet.
+func NewAlphabetUint32StoreAuto(src Uint32Source) (AlphabetUint32Store, error) {
+	var in [256]bool
+	for _, k := range src.AppendKeys([]string(nil)) {
+		for i := 0; i < len(k); i++ {
+			in[k[i]] = true
+		}
+	}
+	return newAlphabetUint32Store(src, &in)
+}
+
+func newAlphabetUint32Store(src Uint32Source, in *[256]bool) (AlphabetUint32Store, error) {
+	var m AlphabetUint32Store
+	// number the bytes in order so that the order of keys is unchanged
+	m.decode = []byte{0}
+	for c := range in {
+		if in[c] {
+			m.code[c] = byte(len(m.decode))
+			m.decode = append(m.decode, byte(c))
+		}
+	}
+	if len(m.decode) > 256 {
+		return AlphabetUint32Store{}, fmt.Errorf("%w: alphabet has all 256 bytes", ErrNotInAlphabet)
+	}
+
+	keys := src.AppendKeys([]string(nil))
+	sort.Strings(keys)
+	coded := make([]string, len(keys))
+	for i, k := range keys {
+		b := []byte(k)
+		for j, c := range b {
+			if b[j] = m.code[c]; b[j] == 0 {
+				return AlphabetUint32Store{}, fmt.Errorf("%w: %q in %q", ErrNotInAlphabet, c, k)
+			}
+		}
+		coded[i] = string(b)
+	}
+	m.m = newUint32StoreSorted(coded, func(i int) uint32 { return src.Get(keys[i]) })
+	return m, nil
+}
+
+// LookupString looks up the supplied string in the map
+func (m *AlphabetUint32Store) LookupString(s string) (uint32, bool) {
+	store := m.m.store
+	if len(store) == 0 {
+		return 0, false
+	}
+	bv := &store[0]
+	for i, n := 0, len(s); i < n; i++ {
+		b := m.code[s[i]]
+		if b < bv.nextOffset || b == 0 {
+			return 0, false
+		}
+		ni := b - bv.nextOffset
+		if uint16(ni) >= bv.nextLen {
+			return 0, false
+		}
+		bv = &store[bv.nextLo+uint32(ni)]
+	}
+	return bv.value, bv.valid
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (m *AlphabetUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	store := m.m.store
+	if len(store) == 0 {
+		return 0, false
+	}
+	bv := &store[0]
+	for _, c := range s {
+		b := m.code[c]
+		if b < bv.nextOffset || b == 0 {
+			return 0, false
+		}
+		ni := b - bv.nextOffset
+		if uint16(ni) >= bv.nextLen {
+			return 0, false
+		}
+		bv = &store[bv.nextLo+uint32(ni)]
+	}
+	return bv.value, bv.valid
+}
+
+// Alphabet returns the bytes of the alphabet in order
+func (m *AlphabetUint32Store) Alphabet() string {
+	if len(m.decode) == 0 {
+		return ""
+	}
+	return string(m.decode[1:])
+}
+
+// Len returns the number of keys in the map
+func (m *AlphabetUint32Store) Len() int { return m.m.Len() }
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (m *AlphabetUint32Store) Walk(fn func(string, uint32) bool) {
+	buf := make([]byte, 0, 256)
+	m.m.walk(func(key []byte, v uint32) bool {
+		buf = buf[:0]
+		for _, b := range key {
+			buf = append(buf, m.decode[b])
+		}
+		return fn(string(buf), v)
+	})
+}
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the AlphabetUint32Store value itself
+func (m *AlphabetUint32Store) SizeInBytes() int { return m.m.SizeInBytes() + cap(m.decode) }
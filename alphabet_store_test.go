@@ -0,0 +1,64 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestAlphabetUint32Store(t *testing.T) {
+	m := make(map[string]uint32, 4000)
+	for len(m) < 4000 {
+		m[fmt.Sprintf("%x", rand.Uint32()>>uint(rand.Intn(32)))] = uint32(len(m))
+	}
+	ms := mapSliceN(m, len(m)/2)
+	ms.out = append(ms.out, "g", "0x1", "FF", "-1")
+	am, err := faststringmap.NewAlphabetUint32Store(ms, "fedcba9876543210")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLookuper(t, "hex", &am, ms)
+	if got, want := am.Alphabet(), "0123456789abcdef"; got != want {
+		t.Errorf("Alphabet got %q want %q", got, want)
+	}
+	full := faststringmap.NewUint32Store(ms)
+	if am.SizeInBytes()*2 > full.SizeInBytes() {
+		t.Errorf("alphabet map %d bytes not much smaller than %d", am.SizeInBytes(), full.SizeInBytes())
+	}
+
+	auto, err := faststringmap.NewAlphabetUint32StoreAuto(ms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLookuper(t, "auto", &auto, ms)
+
+	_, err = faststringmap.NewAlphabetUint32Store(ms, "0123456789abcde")
+	if !errors.Is(err, faststringmap.ErrNotInAlphabet) {
+		t.Errorf("missing f: got %v want ErrNotInAlphabet", err)
+	}
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	_, err = faststringmap.NewAlphabetUint32Store(ms, string(all))
+	if !errors.Is(err, faststringmap.ErrNotInAlphabet) {
+		t.Errorf("all bytes: got %v want ErrNotInAlphabet", err)
+	}
+	_, err = faststringmap.NewAlphabetUint32StoreAuto(mapSliceN(map[string]uint32{string(all): 1}, 1))
+	if !errors.Is(err, faststringmap.ErrNotInAlphabet) {
+		t.Errorf("auto all bytes: got %v want ErrNotInAlphabet", err)
+	}
+
+	var zero faststringmap.AlphabetUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	empty, err := faststringmap.NewAlphabetUint32StoreAuto(mapSlice{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLookuper(t, "empty", &empty, mapSlice{out: []string{"", "a"}})
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+// Thresholds used by NewBest
+const (
+	bestShortKey     = 12  // average key length in bytes up to which a trie is best
+	bestUniqueSuffix = 0.5 // fraction of key bytes not shared with a neighbour above which runs compress well
+)
+
+// NewBest creates the implementation which should be fastest for the keys
+// in src, based on their lengths and how much they share prefixes:
+//
+//   - short keys use a Uint32Store, or a TwoByteRootStore if the first two
+//     bytes of the keys are dense
+//   - long keys which mostly differ soon after the start, such as UUIDs,
+//     use a RadixUint32Store as their long unshared suffixes compress to a
+//     single comparison
+//   - other long keys, such as URLs with long common prefixes, use a
+//     MPHUint32Store as the trie would have many branching nodes
+func NewBest(src Uint32Source) Lookuper {
+	keys := src.AppendKeys([]string(nil))
+	sort.Strings(keys)
+	total, unique := 0, 0
+	for i, k := range keys {
+		// bytes of k after the longest prefix shared with a neighbour
+		shared := 0
+		if i > 0 {
+			shared = commonPrefixLen(keys[i-1], k)
+		}
+		if i+1 < len(keys) {
+			if n := commonPrefixLen(k, keys[i+1]); n > shared {
+				shared = n
+			}
+		}
+		total += len(k)
+		unique += len(k) - shared
+	}
+	src = funcSource{keys: keys, get: src.Get}
+
+	if total <= bestShortKey*len(keys) {
+		m := NewUint32Store(src)
+		if t, ok := NewTwoByteRootStore(m); ok {
+			return &t
+		}
+		return &m
+	}
+	if float64(unique) > bestUniqueSuffix*float64(total) {
+		m := NewRadixUint32Store(src)
+		return &m
+	}
+	m := NewMPHUint32Store(src)
+	return &m
+}
+
+// commonPrefixLen returns the length of the longest common prefix of a and b
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
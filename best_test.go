@@ -0,0 +1,39 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNewBest(t *testing.T) {
+	urls := map[string]uint32{}
+	for i := 0; i < 1000; i++ {
+		urls[fmt.Sprintf("https://example.com/products/category-%d/item-%d", i%10, i)] = uint32(i)
+	}
+	sparse := map[string]uint32{}
+	for i := 0; i < 100; i++ {
+		sparse[string(rune('!'+i%90))+strconv.Itoa(i)] = uint32(i)
+	}
+	for _, tc := range []struct {
+		name string
+		ms   mapSlice
+		want faststringmap.Lookuper
+	}{
+		{"codes", typicalCodeStrings(1000), &faststringmap.TwoByteRootStore{}},
+		{"sparse", mapSliceN(sparse, 50), &faststringmap.Uint32Store{}},
+		{"uuids", uuidStrings(1000), &faststringmap.RadixUint32Store{}},
+		{"urls", mapSliceN(urls, 500), &faststringmap.MPHUint32Store{}},
+		{"empty", mapSlice{out: []string{"", "a"}}, &faststringmap.Uint32Store{}},
+	} {
+		l := faststringmap.NewBest(tc.ms)
+		if got, want := fmt.Sprintf("%T", l), fmt.Sprintf("%T", tc.want); got != want {
+			t.Errorf("%s: got %s want %s", tc.name, got, want)
+		}
+		checkLookuper(t, tc.name, l, tc.ms)
+	}
+}
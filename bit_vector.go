@@ -0,0 +1,106 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "math/bits"
+
+// selectSample is the number of zero bits between samples in bitVector.zeros
+const selectSample = 256
+
+// bitVector is a sequence of bits with a directory of counts of one bits
+// for rank and select queries
+type bitVector struct {
+	words []uint64
+	ranks []uint32 // number of one bits before each word
+	zeros []uint32 // word holding every selectSample'th zero bit
+	n     int      // number of bits
+}
+
+// push appends bit b, which must be followed eventually by a call to finish
+func (v *bitVector) push(b bool) {
+	if v.n%64 == 0 {
+		v.words = append(v.words, 0)
+	}
+	if b {
+		v.words[v.n/64] |= 1 << uint(v.n%64)
+	}
+	v.n++
+}
+
+// finish builds the rank directory once all the bits have been pushed
+func (v *bitVector) finish() {
+	v.words = v.words[:len(v.words):len(v.words)]
+	v.ranks = make([]uint32, len(v.words))
+	v.zeros = v.zeros[:0]
+	n := 0
+	for i, w := range v.words {
+		v.ranks[i] = uint32(n)
+		n += bits.OnesCount64(w)
+		// add samples for the zero bits in this word, ignoring padding
+		zerosAfter := (i+1)*64 - n
+		if pad := (i+1)*64 - v.n; pad > 0 {
+			zerosAfter -= pad
+		}
+		for len(v.zeros)*selectSample < zerosAfter {
+			v.zeros = append(v.zeros, uint32(i))
+		}
+	}
+}
+
+// get returns bit i
+func (v *bitVector) get(i int) bool {
+	return v.words[i/64]&(1<<uint(i%64)) != 0
+}
+
+// onesFrom returns the number of consecutive one bits starting at bit i,
+// which must be followed by a zero bit
+func (v *bitVector) onesFrom(i int) int {
+	n := 0
+	for {
+		w := ^v.words[i/64] >> uint(i%64)
+		if w != 0 {
+			return n + bits.TrailingZeros64(w)
+		}
+		run := 64 - i%64
+		n += run
+		i += run
+	}
+}
+
+// rank1 returns the number of one bits before bit i
+func (v *bitVector) rank1(i int) int {
+	w := i / 64
+	if w == len(v.words) {
+		return int(v.ranks[w-1]) + bits.OnesCount64(v.words[w-1])
+	}
+	return int(v.ranks[w]) + bits.OnesCount64(v.words[w]&(1<<uint(i%64)-1))
+}
+
+// select0 returns the position of zero bit number k counting from 0,
+// which must exist
+func (v *bitVector) select0(k int) int {
+	// find the last word with at most k zero bits before it, which is
+	// between the words holding the samples either side of k
+	lo, hi := int(v.zeros[k/selectSample]), len(v.words)
+	if j := k/selectSample + 1; j < len(v.zeros) {
+		hi = int(v.zeros[j]) + 1
+	}
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if mid*64-int(v.ranks[mid]) <= k {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	z := ^v.words[lo]
+	for r := k - (lo*64 - int(v.ranks[lo])); r > 0; r-- {
+		z &= z - 1 // clear the lowest zero bit
+	}
+	return lo*64 + bits.TrailingZeros64(z)
+}
+
+// sizeInBytes returns the number of bytes of memory held by v
+func (v *bitVector) sizeInBytes() int {
+	return cap(v.words)*8 + cap(v.ranks)*4 + cap(v.zeros)*4
+}
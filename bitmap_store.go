@@ -0,0 +1,219 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"math/bits"
+	"unsafe"
+)
+
+type (
+	// BitmapUint32Store is a fast read only map from string to uint32 like
+	// Uint32Store except that the next bytes of each node are given by a
+	// bitmap and only the children which exist are stored, consecutively,
+	// with the position of a child found by counting the bits below its
+	// byte. Sparse nodes therefore take no more space than dense ones and a
+	// lookup step still has no searching or branching on the node kind.
+	// The zero value is an empty map.
+	BitmapUint32Store struct {
+		nodes   []bitmapNode
+		bitmaps []uint64 // words of the bitmaps of nodes after the first
+	}
+
+	bitmapNode struct {
+		word  uint64 // first word of the bitmap, held in the node to save a memory access
+		value uint32 // value for byte sequence with no more bytes
+		first uint32 // index in nodes of the first child
+		more  uint32 // index in bitmaps of the second word of the bitmap
+		lo    byte   // next byte for bit 0 of the bitmap, a multiple of 64
+		words byte   // number of words in the bitmap
+		valid bool   // is the byte sequence with no more bytes in the map?
+	}
+)
+
+// NewBitmapUint32Store creates from the data supplied in src
+func NewBitmapUint32Store(src Uint32Source) BitmapUint32Store {
+	m := NewUint32Store(src)
+	bm := BitmapUint32Store{nodes: make([]bitmapNode, 1)}
+	// an explicit stack so that the length of the keys does not bound the
+	// depth of the Go stack
+	tasks := []nodeTask{{id: 0, i: m.root}}
+	for len(tasks) > 0 {
+		t := tasks[len(tasks)-1]
+		tasks = bm.set(&m, tasks[:len(tasks)-1], t.id, t.i)
+	}
+	return bm
+}
+
+// set sets nodes[id] to the node for store index i of m, pushing a task
+// onto tasks for each of its children with the lowest byte on top
+func (bm *BitmapUint32Store) set(m *Uint32Store, tasks []nodeTask, id, i uint32) []nodeTask {
+	bv := &m.store[i]
+	var next []byte // bytes which lead to keys
+	for j := uint32(0); j < uint32(bv.nextLen); j++ {
+		if c := &m.store[bv.nextLo+j]; c.valid || c.nextLen > 0 {
+			next = append(next, bv.nextOffset+byte(j))
+		}
+	}
+	nd := bitmapNode{value: bv.value, valid: bv.valid}
+	if len(next) > 0 {
+		// only the words of the bitmap between the lowest and highest bytes
+		nd.lo = next[0] &^ 63
+		nd.words = (next[len(next)-1]-nd.lo)/64 + 1
+		words := make([]uint64, nd.words)
+		for _, b := range next {
+			k := b - nd.lo
+			words[k/64] |= 1 << (k % 64)
+		}
+		nd.word, nd.more = words[0], uint32(len(bm.bitmaps))
+		bm.bitmaps = append(bm.bitmaps, words[1:]...)
+		nd.first = uint32(len(bm.nodes))
+		bm.nodes = append(bm.nodes, make([]bitmapNode, len(next))...)
+	}
+	bm.nodes[id] = nd
+	for j := len(next) - 1; j >= 0; j-- {
+		tasks = append(tasks, nodeTask{id: nd.first + uint32(j), i: bv.nextLo + uint32(next[j]-bv.nextOffset)})
+	}
+	return tasks
+}
+
+// child returns the index of the child of nodes[i] for byte b
+func (bm *BitmapUint32Store) child(i uint32, b byte) (uint32, bool) {
+	nd := &bm.nodes[i]
+	if b < nd.lo {
+		return 0, false
+	}
+	k := b - nd.lo
+	bit := uint64(1) << (k % 64)
+	if k < 64 {
+		if nd.word&bit == 0 {
+			return 0, false
+		}
+		return nd.first + uint32(bits.OnesCount64(nd.word&(bit-1))), true
+	}
+	w := k / 64
+	if w >= nd.words {
+		return 0, false
+	}
+	more := bm.bitmaps[nd.more : nd.more+uint32(w)]
+	if more[w-1]&bit == 0 {
+		return 0, false
+	}
+	n := bits.OnesCount64(nd.word) + bits.OnesCount64(more[w-1]&(bit-1))
+	for _, x := range more[:w-1] {
+		n += bits.OnesCount64(x)
+	}
+	return nd.first + uint32(n), true
+}
+
+// LookupString looks up the supplied string in the map
+func (bm *BitmapUint32Store) LookupString(s string) (uint32, bool) {
+	if len(bm.nodes) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	for j := 0; j < len(s); j++ {
+		var ok bool
+		if i, ok = bm.child(i, s[j]); !ok {
+			return 0, false
+		}
+	}
+	nd := &bm.nodes[i]
+	return nd.value, nd.valid
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (bm *BitmapUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	if len(bm.nodes) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	for _, b := range s {
+		var ok bool
+		if i, ok = bm.child(i, b); !ok {
+			return 0, false
+		}
+	}
+	nd := &bm.nodes[i]
+	return nd.value, nd.valid
+}
+
+// Len returns the number of keys in the map
+func (bm *BitmapUint32Store) Len() int {
+	n := 0
+	for i := range bm.nodes {
+		if bm.nodes[i].valid {
+			n++
+		}
+	}
+	return n
+}
+
+// Walk calls fn for each key in the map in sorted order until fn returns
+// false. It uses an explicit stack, like Uint32Store.Walk, so that the
+// length of the keys does not bound the depth of the Go stack.
+func (bm *BitmapUint32Store) Walk(fn func(string, uint32) bool) {
+	if len(bm.nodes) == 0 {
+		return
+	}
+	var frames [walkStackSize]bitmapFrame
+	stack := frames[:0]
+	key := make([]byte, 0, 256)
+	if !bm.visit(0, key, fn) {
+		return
+	}
+	stack = append(stack, bm.frame(0))
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		for f.x == 0 && f.w+1 < uint32(bm.nodes[f.node].words) {
+			f.w++
+			f.x = bm.bitmaps[bm.nodes[f.node].more+f.w-1]
+		}
+		if f.x == 0 {
+			if stack = stack[:len(stack)-1]; len(stack) > 0 {
+				key = key[:len(key)-1]
+			}
+			continue
+		}
+		nd := &bm.nodes[f.node]
+		b := nd.lo + byte(f.w*64) + byte(bits.TrailingZeros64(f.x))
+		f.x &= f.x - 1
+		c := f.c
+		f.c++
+		key = append(key, b)
+		if !bm.visit(c, key, fn) {
+			return
+		}
+		stack = append(stack, bm.frame(c))
+	}
+}
+
+// bitmapFrame is a node being visited by BitmapUint32Store.Walk
+type bitmapFrame struct {
+	node uint32
+	c    uint32 // index in nodes of the next child to visit
+	w    uint32 // word of the bitmap being visited
+	x    uint64 // bits of word w for the children still to visit
+}
+
+// frame returns the bitmapFrame for starting to visit the children of nodes[i]
+func (bm *BitmapUint32Store) frame(i uint32) bitmapFrame {
+	nd := &bm.nodes[i]
+	f := bitmapFrame{node: i, c: nd.first}
+	if nd.words > 0 {
+		f.x = nd.word
+	}
+	return f
+}
+
+// visit calls fn for nodes[i] if it is a key, returning false if fn stopped the walk
+func (bm *BitmapUint32Store) visit(i uint32, key []byte, fn func(string, uint32) bool) bool {
+	nd := &bm.nodes[i]
+	return !nd.valid || fn(string(key), nd.value)
+}
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the BitmapUint32Store value itself
+func (bm *BitmapUint32Store) SizeInBytes() int {
+	return cap(bm.nodes)*int(unsafe.Sizeof(bitmapNode{})) + cap(bm.bitmaps)*8
+}
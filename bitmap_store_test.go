@@ -0,0 +1,60 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestBitmapUint32Store(t *testing.T) {
+	m := randomSmallStrings(20000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	bm := faststringmap.NewBitmapUint32Store(ms)
+	checkLookuper(t, "random", &bm, ms)
+
+	ms = typicalCodeStrings(1000)
+	ms.out = []string{"", "0", "1000", "-", "-8", "9a"}
+	bm = faststringmap.NewBitmapUint32Store(ms)
+	checkLookuper(t, "codes", &bm, ms)
+
+	// two children spread across the printable bytes
+	ms = mapSliceN(map[string]uint32{"a!": 1, "a~": 2}, 2)
+	ms.out = []string{"a", "a\"", "a}", "b!"}
+	bm = faststringmap.NewBitmapUint32Store(ms)
+	checkLookuper(t, "sparse", &bm, ms)
+	fm := faststringmap.NewUint32Store(ms)
+	if bm.SizeInBytes()*4 > fm.SizeInBytes() {
+		t.Errorf("bitmap %d bytes not much smaller than %d", bm.SizeInBytes(), fm.SizeInBytes())
+	}
+
+	// a node with next bytes in every word of the bitmap
+	mid := map[string]uint32{}
+	for i := 0; i < 30; i++ {
+		mid[string([]byte{byte(i * 8)})] = uint32(i)
+	}
+	ms = mapSliceN(mid, 30)
+	ms.out = []string{"\x01", "\xff", "\x08\x00"}
+	bm = faststringmap.NewBitmapUint32Store(ms)
+	checkLookuper(t, "words", &bm, ms)
+
+	var zero faststringmap.BitmapUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	bm = faststringmap.NewBitmapUint32Store(mapSlice{})
+	checkLookuper(t, "empty", &bm, mapSlice{out: []string{"", "a"}})
+}
+
+func BenchmarkBitmapUint32Store(b *testing.B) {
+	m := typicalCodeStrings(nStrsBench)
+	bm := faststringmap.NewBitmapUint32Store(m)
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := bm.LookupString(m.in[si])
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
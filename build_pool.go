@@ -0,0 +1,59 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// sizes of the blocks used during construction are powers of two from
+// minBuildBufSize to maxBuildBufSize
+const (
+	minBuildBufShift = 4
+	maxBuildBufShift = 20
+	minBuildBufSize  = 1 << minBuildBufShift
+	maxBuildBufSize  = 1 << maxBuildBufShift
+)
+
+// buildPools holds zeroed blocks used during construction for reuse by
+// later builds, one pool for each power of two block size
+var buildPools [maxBuildBufShift - minBuildBufShift + 1]sync.Pool
+
+// WarmBuildPool adds blocks to the pool used during construction which
+// are sufficient for building a map with about nByteValues nodes, so that
+// a later build does not need to allocate them. As with sync.Pool,
+// unused blocks may be released at any garbage collection.
+func WarmBuildPool(nByteValues int) {
+	size := firstBufSize(nByteValues)
+	for nByteValues > 0 {
+		putBuildBlock(make([]byteValue, 0, size))
+		nByteValues -= size
+		if size < maxBuildBufSize {
+			size <<= 1
+		}
+	}
+}
+
+// getBuildBlock returns a zeroed block of length n and capacity size,
+// which must be a power of two from minBuildBufSize to maxBuildBufSize
+func getBuildBlock(n, size int) []byteValue {
+	if a, ok := buildPools[buildPoolIndex(size)].Get().(*[]byteValue); ok {
+		return (*a)[:n]
+	}
+	return make([]byteValue, n, size)
+}
+
+// putBuildBlock zeroes a block from getBuildBlock and returns it to its pool
+func putBuildBlock(a []byteValue) {
+	a = a[:cap(a)]
+	for i := range a {
+		a[i] = byteValue{}
+	}
+	a = a[:0]
+	buildPools[buildPoolIndex(cap(a))].Put(&a)
+}
+
+func buildPoolIndex(size int) int {
+	return bits.TrailingZeros(uint(size)) - minBuildBufShift
+}
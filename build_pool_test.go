@@ -0,0 +1,28 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestBuildPoolReuse(t *testing.T) {
+	faststringmap.WarmBuildPool(100000)
+	// blocks are reused between builds so must not carry over any state
+	for i := 0; i < 5; i++ {
+		m := randomSmallStrings(2000, 8)
+		checkWithMapSlice(t, mapSliceN(m, len(m)/2))
+	}
+}
+
+func BenchmarkBuildUint32Store(b *testing.B) {
+	m := randomSmallStrings(100000, 8)
+	ms := mapSliceN(m, len(m))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		faststringmap.NewUint32Store(ms)
+	}
+}
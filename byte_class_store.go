@@ -0,0 +1,106 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+// ByteClassUint32Store is a fast read only map from string to uint32 which
+// translates every byte through a table of byte classes, in both the keys
+// it is built from and the strings looked up, so that bytes in the same
+// class are treated as equal. For example a table mapping '_' to '-' and
+// upper case letters to lower case makes "Content_Type" and
+// "content-type" the same key. Bytes are translated while following the
+// trie, so lookups do not allocate a translated copy of the string.
+// The zero value is an empty map.
+type ByteClassUint32Store struct {
+	m     Uint32Store // translated keys
+	table [256]byte
+}
+
+// IdentityByteClasses returns a table of byte classes mapping each byte to
+// itself, which can be changed and passed to NewByteClassUint32Store
+func IdentityByteClasses() [256]byte {
+	var t [256]byte
+	for i := range t {
+		t[i] = byte(i)
+	}
+	return t
+}
+
+// NewByteClassUint32Store creates from the data supplied in src with each
+// byte translated by table. If several keys are the same once translated
+// then the value is that of the first of them in sorted order.
+func NewByteClassUint32Store(src Uint32Source, table [256]byte) ByteClassUint32Store {
+	keys := src.AppendKeys([]string(nil))
+	sort.Strings(keys)
+	orig := make(map[string]string, len(keys)) // translated key to first original key
+	translated := keys[:0]
+	for _, k := range keys {
+		b := []byte(k)
+		for i, c := range b {
+			b[i] = table[c]
+		}
+		tk := string(b)
+		if _, ok := orig[tk]; !ok {
+			orig[tk] = k
+			translated = append(translated, tk)
+		}
+	}
+	return ByteClassUint32Store{
+		m: NewUint32Store(funcSource{
+			keys: translated,
+			get:  func(tk string) uint32 { return src.Get(orig[tk]) },
+		}),
+		table: table,
+	}
+}
+
+// LookupString looks up the supplied string in the map translating its bytes
+func (m *ByteClassUint32Store) LookupString(s string) (uint32, bool) {
+	store := m.m.store
+	if len(store) == 0 {
+		return 0, false
+	}
+	bv := &store[0]
+	for i, n := 0, len(s); i < n; i++ {
+		b := m.table[s[i]]
+		if b < bv.nextOffset {
+			return 0, false
+		}
+		ni := b - bv.nextOffset
+		if uint16(ni) >= bv.nextLen {
+			return 0, false
+		}
+		bv = &store[bv.nextLo+uint32(ni)]
+	}
+	return bv.value, bv.valid
+}
+
+// LookupBytes looks up the supplied byte slice in the map translating its bytes
+func (m *ByteClassUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	store := m.m.store
+	if len(store) == 0 {
+		return 0, false
+	}
+	bv := &store[0]
+	for _, c := range s {
+		b := m.table[c]
+		if b < bv.nextOffset {
+			return 0, false
+		}
+		ni := b - bv.nextOffset
+		if uint16(ni) >= bv.nextLen {
+			return 0, false
+		}
+		bv = &store[bv.nextLo+uint32(ni)]
+	}
+	return bv.value, bv.valid
+}
+
+// Len returns the number of keys in the map, counting keys which are the
+// same once translated once
+func (m *ByteClassUint32Store) Len() int { return m.m.Len() }
+
+// Walk calls fn for each translated key in the map in sorted order until
+// fn returns false
+func (m *ByteClassUint32Store) Walk(fn func(string, uint32) bool) { m.m.Walk(fn) }
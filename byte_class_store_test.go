@@ -0,0 +1,61 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestByteClassUint32Store(t *testing.T) {
+	table := faststringmap.IdentityByteClasses()
+	table['_'] = '-'
+	table[' '] = '-'
+	for c := 'a'; c <= 'z'; c++ {
+		table[c] -= 'a' - 'A'
+	}
+	m := map[string]uint32{
+		"en-gb": 1,
+		"EN_GB": 2, // same as en-gb once translated
+		"en-us": 3,
+		"fr":    4,
+	}
+	bm := faststringmap.NewByteClassUint32Store(mapSliceN(m, len(m)), table)
+	for _, tc := range []struct {
+		key   string
+		value uint32
+		ok    bool
+	}{
+		{"en-gb", 2, true},
+		{"EN GB", 2, true},
+		{"En_Gb", 2, true},
+		{"en_US", 3, true},
+		{"FR", 4, true},
+		{"en.gb", 0, false},
+		{"en", 0, false},
+	} {
+		if v, ok := bm.LookupString(tc.key); v != tc.value || ok != tc.ok {
+			t.Errorf("LookupString %q: got %d, %v want %d, %v", tc.key, v, ok, tc.value, tc.ok)
+		}
+		if v, ok := bm.LookupBytes([]byte(tc.key)); v != tc.value || ok != tc.ok {
+			t.Errorf("LookupBytes %q: got %d, %v want %d, %v", tc.key, v, ok, tc.value, tc.ok)
+		}
+	}
+	var keys []string
+	bm.Walk(func(k string, _ uint32) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if want := []string{"EN-GB", "EN-US", "FR"}; !equalStrings(keys, want) {
+		t.Errorf("Walk got %q want %q", keys, want)
+	}
+
+	// the identity table gives an ordinary map
+	ms := mapSliceN(randomSmallStrings(1000, 8), 500)
+	bm = faststringmap.NewByteClassUint32Store(ms, faststringmap.IdentityByteClasses())
+	checkLookuper(t, "identity", &bm, ms)
+
+	var zero faststringmap.ByteClassUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+}
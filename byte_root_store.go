@@ -0,0 +1,82 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// ByteRootStore is a Uint32Store with a 256 entry table giving the byteValue
+// for the first byte of a string. Indexing the table with a byte needs no
+// range or bounds check, removing the branches for the root node which is
+// visited by every lookup.
+type ByteRootStore struct {
+	m     Uint32Store
+	table *[256]uint32 // store index for each first byte, 0 if none
+}
+
+// NewByteRootStore creates a ByteRootStore for m
+func NewByteRootStore(m Uint32Store) ByteRootStore {
+	m = m.rooted()
+	t := ByteRootStore{m: m, table: new([256]uint32)}
+	if len(m.store) == 0 {
+		return t
+	}
+	root := &m.store[0]
+	for j := uint32(0); j < uint32(root.nextLen); j++ {
+		i := root.nextLo + j
+		if next := &m.store[i]; next.valid || next.nextLen > 0 {
+			t.table[root.nextOffset+byte(j)] = i
+		}
+	}
+	return t
+}
+
+// LookupString looks up the supplied string in the map
+func (t *ByteRootStore) LookupString(s string) (uint32, bool) {
+	if len(s) == 0 || t.table == nil {
+		return t.m.LookupString(s)
+	}
+	i := t.table[s[0]]
+	if i == 0 {
+		return 0, false
+	}
+	bv := &t.m.store[i]
+	for p, n := 1, len(s); p < n; p++ {
+		b := s[p]
+		if b < bv.nextOffset {
+			return 0, false
+		}
+		ni := b - bv.nextOffset
+		if uint16(ni) >= bv.nextLen {
+			return 0, false
+		}
+		bv = &t.m.store[bv.nextLo+uint32(ni)]
+	}
+	return bv.value, bv.valid
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (t *ByteRootStore) LookupBytes(s []byte) (uint32, bool) {
+	if len(s) == 0 || t.table == nil {
+		return t.m.LookupBytes(s)
+	}
+	i := t.table[s[0]]
+	if i == 0 {
+		return 0, false
+	}
+	bv := &t.m.store[i]
+	for _, b := range s[1:] {
+		if b < bv.nextOffset {
+			return 0, false
+		}
+		ni := b - bv.nextOffset
+		if uint16(ni) >= bv.nextLen {
+			return 0, false
+		}
+		bv = &t.m.store[bv.nextLo+uint32(ni)]
+	}
+	return bv.value, bv.valid
+}
+
+// Len returns the number of keys in the map
+func (t *ByteRootStore) Len() int { return t.m.Len() }
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (t *ByteRootStore) Walk(fn func(string, uint32) bool) { t.m.Walk(fn) }
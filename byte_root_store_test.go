@@ -0,0 +1,40 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestByteRootStore(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	br := faststringmap.NewByteRootStore(faststringmap.NewUint32Store(ms))
+	checkLookuper(t, "random", &br, ms)
+
+	ms = typicalCodeStrings(1000)
+	ms.out = []string{"", "0", "00", "1000", "-", "-8", "9a", "~~", "\xff"}
+	br = faststringmap.NewByteRootStore(faststringmap.NewUint32Store(ms))
+	checkLookuper(t, "codes", &br, ms)
+
+	var zero faststringmap.ByteRootStore
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	br = faststringmap.NewByteRootStore(faststringmap.Uint32Store{})
+	checkLookuper(t, "zero store", &br, mapSlice{out: []string{"", "a"}})
+}
+
+func BenchmarkByteRootStore(b *testing.B) {
+	m := typicalCodeStrings(nStrsBench)
+	fm := faststringmap.NewByteRootStore(faststringmap.NewUint32Store(m))
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := fm.LookupString(m.in[si])
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
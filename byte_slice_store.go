@@ -0,0 +1,78 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+type (
+	// ByteSliceStore is a fast read only map from string to []byte.
+	// All the values are held in a single contiguous blob and lookups
+	// return sub-slices of it, so there is one allocation for all values.
+	// Like Uint32Store it can be saved with WriteTo or MarshalBinary and
+	// loaded with UnmarshalBinary without rebuilding.
+	ByteSliceStore struct {
+		index Uint32Store // key to index in offs
+		offs  []uint32    // value i is blob[offs[i]:offs[i+1]]
+		blob  []byte
+	}
+
+	// ByteSliceSource is for supplying data to initialise ByteSliceStore
+	ByteSliceSource interface {
+		// AppendKeys should append the keys of the maps to the supplied slice and return the resulting slice
+		AppendKeys([]string) []string
+		// Get should return the value for the supplied key
+		Get(string) []byte
+	}
+)
+
+// NewByteSliceStore creates from the data supplied in src.
+// The values are copied so src may reuse its byte slices.
+// The blob is indexed by uint32 offsets, so it returns an error wrapping
+// ErrTooLarge if the values total more than math.MaxUint32 bytes.
+func NewByteSliceStore(src ByteSliceSource) (ByteSliceStore, error) {
+	keys := src.AppendKeys([]string(nil))
+	sort.Strings(keys)
+	offs := make([]uint32, 1, len(keys)+1)
+	var blob []byte
+	for _, k := range keys {
+		v := src.Get(k)
+		if uint64(len(blob))+uint64(len(v)) > math.MaxUint32 {
+			return ByteSliceStore{}, fmt.Errorf("%w: values total more than %d bytes", ErrTooLarge, uint64(math.MaxUint32))
+		}
+		blob = append(blob, v...)
+		offs = append(offs, uint32(len(blob)))
+	}
+	return ByteSliceStore{
+		index: newUint32StoreSorted(keys, func(i int) uint32 { return uint32(i) }),
+		offs:  offs,
+		blob:  blob,
+	}, nil
+}
+
+// LookupString looks up the supplied string in the map.
+// The returned slice must not be modified.
+func (m *ByteSliceStore) LookupString(s string) ([]byte, bool) {
+	if i, ok := m.index.LookupString(s); ok {
+		return m.value(i), true
+	}
+	return nil, false
+}
+
+// LookupBytes looks up the supplied byte slice in the map.
+// The returned slice must not be modified.
+func (m *ByteSliceStore) LookupBytes(s []byte) ([]byte, bool) {
+	if i, ok := m.index.LookupBytes(s); ok {
+		return m.value(i), true
+	}
+	return nil, false
+}
+
+// value returns value i with capacity limited so appending to it cannot overwrite the blob
+func (m *ByteSliceStore) value(i uint32) []byte {
+	lo, hi := m.offs[i], m.offs[i+1]
+	return m.blob[lo:hi:hi]
+}
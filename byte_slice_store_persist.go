@@ -0,0 +1,131 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Persisted format of ByteSliceStore, all integers little-endian:
+//
+//	header (byteSliceHeaderSize bytes):
+//	  magic      [4]byte "FSMB"
+//	  version    uint8
+//	  reserved   [3]byte
+//	  values     uint32  number of values
+//	  blob       uint32  number of bytes of values
+//	  checksum   uint32  CRC-32C of the offsets and the blob
+//	index, the Uint32Store from key to value number in its persisted form
+//	offsets, values+1 uint32s, value i is blob[offsets[i]:offsets[i+1]]
+//	blob
+const (
+	byteSliceMagic      = "FSMB"
+	byteSliceVersion    = 1
+	byteSliceHeaderSize = 20
+)
+
+// WriteTo writes the map to w in a form which can be loaded without rebuilding
+func (m *ByteSliceStore) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(m.appendBinary(nil))
+	return int64(n), err
+}
+
+// MarshalBinary returns the map in a form which can be loaded without rebuilding
+func (m *ByteSliceStore) MarshalBinary() ([]byte, error) {
+	return m.appendBinary(nil), nil
+}
+
+// appendBinary appends the persisted form of the map to b
+func (m *ByteSliceStore) appendBinary(b []byte) []byte {
+	offs := m.offs
+	if len(offs) == 0 {
+		offs = []uint32{0}
+	}
+	start := len(b)
+	b = append(b, make([]byte, byteSliceHeaderSize)...)
+	b = m.index.appendBinary(b)
+	data := len(b)
+	for _, o := range offs {
+		b = append(b, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(b[len(b)-4:], o)
+	}
+	b = append(b, m.blob...)
+
+	hdr := b[start:]
+	copy(hdr, byteSliceMagic)
+	hdr[4] = byteSliceVersion
+	binary.LittleEndian.PutUint32(hdr[8:], uint32(len(offs)-1))
+	binary.LittleEndian.PutUint32(hdr[12:], uint32(len(m.blob)))
+	binary.LittleEndian.PutUint32(hdr[16:], crc32.Checksum(b[data:], crcTable))
+	return b
+}
+
+// UnmarshalBinary loads a map from data returned by MarshalBinary or
+// written by WriteTo. The error wraps ErrInvalidData if the data is
+// malformed, in which case the map is unchanged. The values are copied
+// out of data, which may then be reused.
+func (m *ByteSliceStore) UnmarshalBinary(data []byte) error {
+	if len(data) < byteSliceHeaderSize || string(data[:4]) != byteSliceMagic {
+		return fmt.Errorf("%w: bad magic", ErrInvalidData)
+	}
+	if data[4] != byteSliceVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidData, data[4])
+	}
+	if data[5] != 0 || data[6] != 0 || data[7] != 0 {
+		return fmt.Errorf("%w: reserved header bytes set", ErrInvalidData)
+	}
+	values := binary.LittleEndian.Uint32(data[8:])
+	blobLen := binary.LittleEndian.Uint32(data[12:])
+	checksum := binary.LittleEndian.Uint32(data[16:])
+	data = data[byteSliceHeaderSize:]
+
+	h, err := parseHeader(data)
+	if err != nil {
+		return err
+	}
+	indexLen := persistHeaderSize + uint64(h.nodes)*persistNodeSize
+	want := indexLen + (uint64(values)+1)*4 + uint64(blobLen)
+	if uint64(len(data)) != want {
+		return fmt.Errorf("%w: %d bytes after header want %d", ErrInvalidData, len(data), want)
+	}
+	var index Uint32Store
+	if err := index.UnmarshalBinary(data[:indexLen]); err != nil {
+		return err
+	}
+	data = data[indexLen:]
+	if crc := crc32.Checksum(data, crcTable); crc != checksum {
+		return fmt.Errorf("%w: checksum %08x want %08x", ErrInvalidData, crc, checksum)
+	}
+
+	if index.Len() != int(values) {
+		return fmt.Errorf("%w: %d keys for %d values", ErrInvalidData, index.Len(), values)
+	}
+	var bad error
+	index.Walk(func(k string, v uint32) bool {
+		if v >= values {
+			bad = fmt.Errorf("%w: key %q has value %d of %d", ErrInvalidData, k, v, values)
+		}
+		return bad == nil
+	})
+	if bad != nil {
+		return bad
+	}
+
+	offs := make([]uint32, values+1)
+	for i := range offs {
+		offs[i] = binary.LittleEndian.Uint32(data[i*4:])
+		if i > 0 && offs[i] < offs[i-1] {
+			return fmt.Errorf("%w: offset %d decreases", ErrInvalidData, i)
+		}
+	}
+	if offs[0] != 0 || offs[values] != blobLen {
+		return fmt.Errorf("%w: offsets do not cover the %d bytes of values", ErrInvalidData, blobLen)
+	}
+	blob := make([]byte, blobLen)
+	copy(blob, data[len(offs)*4:])
+	*m = ByteSliceStore{index: index, offs: offs, blob: blob}
+	return nil
+}
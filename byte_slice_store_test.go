@@ -0,0 +1,128 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+type byteSliceSource map[string][]byte
+
+func (s byteSliceSource) AppendKeys(a []string) []string {
+	for k := range s {
+		a = append(a, k)
+	}
+	return a
+}
+
+func (s byteSliceSource) Get(k string) []byte { return s[k] }
+
+func TestByteSliceStore(t *testing.T) {
+	src := byteSliceSource{
+		"a":   []byte("apple"),
+		"b":   []byte("banana"),
+		"bc":  nil,
+		"xyz": []byte("x"),
+	}
+	fm, err := faststringmap.NewByteSliceStore(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range src {
+		if v, ok := fm.LookupString(k); !ok || !bytes.Equal(v, want) {
+			t.Errorf("LookupString %q: got %q, %v want %q, true", k, v, ok, want)
+		}
+		if v, ok := fm.LookupBytes([]byte(k)); !ok || !bytes.Equal(v, want) {
+			t.Errorf("LookupBytes %q: got %q, %v want %q, true", k, v, ok, want)
+		}
+	}
+	for _, k := range []string{"", "c", "ab", "xy"} {
+		if v, ok := fm.LookupString(k); ok {
+			t.Errorf("%q present when not expected, got %q", k, v)
+		}
+	}
+
+	// appending to a returned value must not change other values
+	v, _ := fm.LookupString("a")
+	_ = append(v, "zzz"...)
+	if v, _ := fm.LookupString("b"); string(v) != "banana" {
+		t.Errorf("b changed to %q after append to a", v)
+	}
+}
+
+func TestByteSliceStorePersist(t *testing.T) {
+	src := byteSliceSource{"a": []byte("apple"), "b": []byte("banana"), "bc": nil}
+	fm, err := faststringmap.NewByteSliceStore(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := fm.MarshalBinary()
+	var buf bytes.Buffer
+	if n, err := fm.WriteTo(&buf); err != nil || n != int64(len(data)) || !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("WriteTo wrote %d bytes, %v, differing from MarshalBinary", n, err)
+	}
+
+	var loaded faststringmap.ByteSliceStore
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range src {
+		if v, ok := loaded.LookupString(k); !ok || !bytes.Equal(v, want) {
+			t.Errorf("LookupString %q: got %q, %v want %q, true", k, v, ok, want)
+		}
+	}
+	if v, ok := loaded.LookupString("c"); ok {
+		t.Errorf("c present when not expected, got %q", v)
+	}
+
+	var zero, empty faststringmap.ByteSliceStore
+	zdata, _ := zero.MarshalBinary()
+	if err := empty.UnmarshalBinary(zdata); err != nil {
+		t.Fatalf("zero map: %v", err)
+	}
+	if v, ok := empty.LookupString(""); ok {
+		t.Errorf("empty map has %q", v)
+	}
+
+	for i := range data {
+		if i >= 25 && i < 28 {
+			continue // reserved bytes of the index header, ignored as by Uint32Store
+		}
+		bad := append([]byte(nil), data...)
+		bad[i] ^= 0x80
+		if err := loaded.UnmarshalBinary(bad); !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("byte %d changed: got error %v want %v", i, err, faststringmap.ErrInvalidData)
+		}
+	}
+	for _, n := range []int{0, 19, 20, len(data) - 1} {
+		if err := loaded.UnmarshalBinary(data[:n]); !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("%d of %d bytes: got error %v want %v", n, len(data), err, faststringmap.ErrInvalidData)
+		}
+	}
+	if err := loaded.UnmarshalBinary(append(data, 0)); !errors.Is(err, faststringmap.ErrInvalidData) {
+		t.Errorf("extra byte: got error %v want %v", err, faststringmap.ErrInvalidData)
+	}
+}
+
+func TestByteSliceStoreTooLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("reserves 4GiB of address space")
+	}
+	if strconv.IntSize < 64 {
+		t.Skip("values over 4GiB need 64-bit ints")
+	}
+	// the memory of the value is never touched so is not committed, and
+	// the size is not a constant so that this builds for 32-bit targets
+	size := uint64(math.MaxUint32) + 1
+	huge := make([]byte, size)
+	_, err := faststringmap.NewByteSliceStore(byteSliceSource{"a": []byte("apple"), "b": huge})
+	if !errors.Is(err, faststringmap.ErrTooLarge) {
+		t.Errorf("got error %v want %v", err, faststringmap.ErrTooLarge)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestFoldCase(t *testing.T) {
+	for _, c := range []struct{ s, want string }{
+		{"Hello, World", "hello, world"},
+		{"Straße STRASSE ẞ", "strasse strasse ss"},
+		{"ſ S s K", "s s s k"},
+		{"ΣΊΣΥΦΟΣ ς", "σίσυφοσ σ"},
+		{"ﬃ ﬅ", "ffi st"},
+		{"İ ı I", "i̇ ı i"},
+		{"ᾼ", "αι"},
+		{"Ꭰ ꭰ ᏸ", "Ꭰ Ꭰ Ᏸ"}, // Cherokee folds to upper case
+		{"日本語 123", "日本語 123"},
+	} {
+		if got := faststringmap.FoldCase(c.s); got != c.want {
+			t.Errorf("%q: got %q want %q", c.s, got, c.want)
+		}
+	}
+
+	// folding is idempotent, and equivalent runes fold the same
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		f := faststringmap.FoldCase(string(r))
+		if ff := faststringmap.FoldCase(f); ff != f {
+			t.Errorf("%U: folded %q then %q", r, f, ff)
+		}
+		if o := unicode.SimpleFold(r); faststringmap.FoldCase(string(o)) != f {
+			t.Errorf("%U and %U fold to %q and %q", r, o, f, faststringmap.FoldCase(string(o)))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+// Command faststringmapgen writes a Go source file declaring a
+// faststringmap.Uint32Store built from a list of keys and values, so the
+// keys need not be read, sorted and built into a trie at startup. The map
+// is held in the binary in its persisted form, a string constant, which
+// MustUnmarshalUint32Store loads when the package is initialised. That
+// is not free: it checks the CRC of the data and decodes every node into a
+// newly allocated slice, so init time and heap use grow linearly with the
+// number of nodes, and the binary holds the constant as well as the heap
+// holding the nodes. For example:
+//
+//	//go:generate faststringmapgen -in codes.txt -out codes_map.go -pkg codes -var Codes
+//
+// Each line of the input is a key and a value separated by the last tab on
+// the line. The value is a decimal uint32. A key starting with a double
+// quote is unquoted as a Go string literal, allowing any bytes in keys.
+// Blank lines are ignored.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func main() {
+	in := flag.String("in", "", "input file of keys and values, standard input if empty")
+	out := flag.String("out", "", "output Go file, standard output if empty")
+	pkg := flag.String("pkg", "", "package name of the output (required)")
+	name := flag.String("var", "", "name of the variable holding the map (required)")
+	flag.Parse()
+	if *pkg == "" || *name == "" || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*in, *out, *pkg, *name); err != nil {
+		fmt.Fprintln(os.Stderr, "faststringmapgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg, name string) error {
+	r := io.Reader(os.Stdin)
+	if in != "" {
+		f, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	src, err := faststringmap.ReadUint32Source(r)
+	if err != nil {
+		return err
+	}
+	code, err := generate(src, pkg, name, in)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(out, code, 0o666)
+}
+
+// generate returns the formatted Go source declaring the map for src
+func generate(src faststringmap.Uint32MapSource, pkg, name, in string) ([]byte, error) {
+	m := faststringmap.NewUint32Store(src)
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	from := ""
+	if in != "" {
+		from = " from " + in
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by faststringmapgen%s; DO NOT EDIT.\n\n", from)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/sensiblecodeio/faststringmap\"\n\n")
+	fmt.Fprintf(&b, "// %s is a map of %d keys\n", name, len(src))
+	fmt.Fprintf(&b, "var %s = faststringmap.MustUnmarshalUint32Store(%+q)\n", name, data)
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestGenerate(t *testing.T) {
+	input := "a\t1\n\n\"tab\\there\"\t2\nkey with spaces\t 4294967295\n\"\"\t3\n"
+	src, err := faststringmap.ReadUint32Source(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := generate(src, "codes", "Codes", "codes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "codes_map.go", code, 0)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, code)
+	}
+	if f.Name.Name != "codes" {
+		t.Errorf("package got %s want codes", f.Name.Name)
+	}
+
+	// load the map from the literal in the generated source
+	var lit string
+	ast.Inspect(f, func(n ast.Node) bool {
+		if bl, ok := n.(*ast.BasicLit); ok && bl.Kind == token.STRING && lit == "" && bl.Value != `"github.com/sensiblecodeio/faststringmap"` {
+			lit = bl.Value
+		}
+		return true
+	})
+	data, err := strconv.Unquote(lit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := faststringmap.MustUnmarshalUint32Store(data)
+	if got := m.ToGoMap(); len(got) != 4 || got["a"] != 1 || got["tab\there"] != 2 || got[""] != 3 || got["key with spaces"] != 4294967295 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestReadSourceErrors(t *testing.T) {
+	for _, input := range []string{"novalue\n", "a\tx\n", "a\t4294967296\n", "\"a\t1\n", "a\t1\na\t2\n"} {
+		if _, err := faststringmap.ReadUint32Source(strings.NewReader(input)); err == nil {
+			t.Errorf("%q: got no error", input)
+		}
+	}
+}
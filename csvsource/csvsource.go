@@ -0,0 +1,116 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+// Package csvsource builds faststringmap maps from delimited files such
+// as CSV and TSV exports of lookup tables, taking the key and the value of
+// each record from given columns.
+package csvsource
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+// Options says how to read the records of a delimited file
+type Options struct {
+	Comma    rune // field delimiter, ',' if zero, '\t' for TSV
+	Header   bool // whether the first record is a header to skip
+	KeyCol   int  // index of the column holding the key
+	ValueCol int  // index of the column holding the value
+
+	// ParseValue parses the value column, or if nil the value is a decimal uint32
+	ParseValue func(string) (uint32, error)
+}
+
+// Read reads the keys and values of the records in r. The error wraps
+// faststringmap.ErrDuplicateKey if a key appears more than once. Quotes
+// are as in RFC 4180 for CSV, but are not special in TSV, where each line
+// is a record and tabs only separate fields.
+func Read(r io.Reader, opts Options) (faststringmap.Uint32MapSource, error) {
+	var read func() ([]string, error)
+	if opts.Comma == '\t' {
+		read = tsvReader(r)
+	} else {
+		cr := csv.NewReader(r)
+		cr.ReuseRecord = true
+		if opts.Comma != 0 {
+			cr.Comma = opts.Comma
+		}
+		read = cr.Read
+	}
+	parse := opts.ParseValue
+	if parse == nil {
+		parse = parseUint32
+	}
+	src := make(faststringmap.Uint32MapSource)
+	for n := 1; ; n++ {
+		rec, err := read()
+		if err == io.EOF {
+			return src, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csvsource: %w", err)
+		}
+		if n == 1 && opts.Header {
+			continue
+		}
+		if opts.KeyCol < 0 || opts.ValueCol < 0 || opts.KeyCol >= len(rec) || opts.ValueCol >= len(rec) {
+			return nil, fmt.Errorf("csvsource: record %d: %d fields want columns %d and %d", n, len(rec), opts.KeyCol, opts.ValueCol)
+		}
+		k := rec[opts.KeyCol]
+		v, err := parse(rec[opts.ValueCol])
+		if err != nil {
+			return nil, fmt.Errorf("csvsource: record %d: value %q: %w", n, rec[opts.ValueCol], err)
+		}
+		if _, ok := src[k]; ok {
+			return nil, fmt.Errorf("csvsource: record %d: %w: %q", n, faststringmap.ErrDuplicateKey, k)
+		}
+		src[k] = v
+	}
+}
+
+// Build creates a map from the records in r, read as by Read
+func Build(r io.Reader, opts Options) (faststringmap.Uint32Store, error) {
+	src, err := Read(r, opts)
+	if err != nil {
+		return faststringmap.Uint32Store{}, err
+	}
+	return faststringmap.NewUint32Store(src), nil
+}
+
+// BuildFile creates a map from the records in the named file, read as by Read
+func BuildFile(name string, opts Options) (faststringmap.Uint32Store, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return faststringmap.Uint32Store{}, err
+	}
+	defer f.Close()
+	return Build(f, opts)
+}
+
+// tsvReader returns a function reading the next record from r, which is
+// a line with fields separated by tabs
+func tsvReader(r io.Reader) func() ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<24)
+	return func() ([]string, error) {
+		if !sc.Scan() {
+			if err := sc.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		return strings.Split(strings.TrimSuffix(sc.Text(), "\r"), "\t"), nil
+	}
+}
+
+func parseUint32(s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	return uint32(v), err
+}
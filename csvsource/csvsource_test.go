@@ -0,0 +1,98 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package csvsource_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+	"github.com/sensiblecodeio/faststringmap/csvsource"
+)
+
+func TestBuild(t *testing.T) {
+	for name, c := range map[string]struct {
+		data string
+		opts csvsource.Options
+	}{
+		"csv": {
+			"code,name,id\nGB,\"United Kingdom, The\",1\nFR,France,2\r\n\"\"\"X\"\"\",Quoted,3\n",
+			csvsource.Options{Header: true, KeyCol: 0, ValueCol: 2},
+		},
+		"tsv": {
+			"1\tGB\n2\tFR\r\n3\t\"X\"\n",
+			csvsource.Options{Comma: '\t', KeyCol: 1, ValueCol: 0},
+		},
+		"semicolon hex": {
+			"GB;0x1\nFR;0x2\n\"X\";0x3\n",
+			csvsource.Options{Comma: ';', KeyCol: 0, ValueCol: 1, ParseValue: func(s string) (uint32, error) {
+				v, err := strconv.ParseUint(s, 0, 32)
+				return uint32(v), err
+			}},
+		},
+	} {
+		fm, err := csvsource.Build(strings.NewReader(c.data), c.opts)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		want := map[string]uint32{"GB": 1, "FR": 2, `"X"`: 3}
+		if name == "semicolon hex" {
+			want = map[string]uint32{"GB": 1, "FR": 2, "X": 3}
+		}
+		if fm.Len() != len(want) {
+			t.Errorf("%s: got %d keys want %d", name, fm.Len(), len(want))
+		}
+		for k, v := range want {
+			if got, ok := fm.LookupString(k); !ok || got != v {
+				t.Errorf("%s: %q got %d, %v want %d, true", name, k, got, ok, v)
+			}
+		}
+	}
+}
+
+func TestBuildFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "codes.tsv")
+	if err := os.WriteFile(name, []byte("GB\t1\nFR\t2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fm, err := csvsource.BuildFile(name, csvsource.Options{Comma: '\t', ValueCol: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := fm.LookupString("FR"); !ok || v != 2 {
+		t.Errorf("FR: got %d, %v want 2, true", v, ok)
+	}
+	if _, err := csvsource.BuildFile(filepath.Join(t.TempDir(), "missing"), csvsource.Options{}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: got error %v want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	opts := csvsource.Options{ValueCol: 1}
+	for name, data := range map[string]string{
+		"value":     "a,1\nb,x\n",
+		"range":     "a,4294967296\n",
+		"columns":   "a\n",
+		"quotes":    "\"a,1\n",
+		"duplicate": "a,1\nb,2\na,3\n",
+	} {
+		_, err := csvsource.Read(strings.NewReader(data), opts)
+		if err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+		if name == "duplicate" && !errors.Is(err, faststringmap.ErrDuplicateKey) {
+			t.Errorf("%s: got error %v want %v", name, err, faststringmap.ErrDuplicateKey)
+		}
+	}
+
+	for _, opts := range []csvsource.Options{{KeyCol: -1, ValueCol: 1}, {KeyCol: 0, ValueCol: -1}} {
+		if _, err := csvsource.Read(strings.NewReader("a,1\n"), opts); err == nil {
+			t.Errorf("%+v: got no error", opts)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type (
+	// ExternalUint32StoreBuilder creates a Uint32Store from more keys than
+	// fit in memory. Keys and values are buffered up to a memory limit, then
+	// sorted and written to a temporary file as a run. Build merges the runs
+	// and builds the trie from the merged stream, so only the map itself
+	// and a buffer per run need to fit in memory. Runs are only open while
+	// they are written or merged, and at most mergeFanIn at once, so there
+	// can be many more runs than file descriptors. It is not safe for
+	// concurrent use, and Close must be called to remove the temporary files.
+	ExternalUint32StoreBuilder struct {
+		dir      string
+		maxBytes int
+		entries  []builderEntry
+		bytes    int      // memory used by entries, roughly
+		runs     []string // names of the sorted runs written so far
+	}
+
+	// runReader reads the entries of a run in order
+	runReader struct {
+		r     *bufio.Reader
+		key   []byte // current key
+		value uint32 // current value
+	}
+
+	// runWriter writes the entries of a run
+	runWriter struct {
+		w   *bufio.Writer
+		buf [binary.MaxVarintLen64]byte
+	}
+
+	// runHeap is a heap of runReaders with the smallest current key first
+	runHeap []*runReader
+)
+
+const (
+	// entryOverhead is the memory used by a buffered entry besides its key
+	entryOverhead = 32
+	// mergeFanIn is the most runs merged at once. Build merges groups of
+	// runs into longer runs until there are no more than this.
+	mergeFanIn = 64
+)
+
+// NewExternalUint32StoreBuilder creates an ExternalUint32StoreBuilder
+// writing temporary files in dir, or the default directory for temporary
+// files if dir is empty, whenever the keys buffered use more than about
+// maxBytes of memory
+func NewExternalUint32StoreBuilder(dir string, maxBytes int) *ExternalUint32StoreBuilder {
+	return &ExternalUint32StoreBuilder{dir: dir, maxBytes: maxBytes}
+}
+
+// Add adds key k with value v, writing a run to a temporary file if the
+// memory limit is reached
+func (b *ExternalUint32StoreBuilder) Add(k string, v uint32) error {
+	b.entries = append(b.entries, builderEntry{key: k, value: v})
+	b.bytes += len(k) + entryOverhead
+	if b.bytes > b.maxBytes {
+		return b.spill()
+	}
+	return nil
+}
+
+// Runs returns the number of runs written to temporary files so far
+func (b *ExternalUint32StoreBuilder) Runs() int { return len(b.runs) }
+
+// sortEntries sorts the buffered entries by key
+func (b *ExternalUint32StoreBuilder) sortEntries() {
+	sort.Slice(b.entries, func(i, j int) bool { return b.entries[i].key < b.entries[j].key })
+}
+
+// spill writes the buffered entries to a new run
+func (b *ExternalUint32StoreBuilder) spill() error {
+	b.sortEntries()
+	err := b.writeRun(func(w *runWriter) error {
+		for _, e := range b.entries {
+			w.write(e.key, e.value)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i := range b.entries {
+		b.entries[i] = builderEntry{} // allow keys to be garbage collected
+	}
+	b.entries, b.bytes = b.entries[:0], 0
+	return nil
+}
+
+// writeRun creates a new run and calls fill to write its entries in order
+func (b *ExternalUint32StoreBuilder) writeRun(fill func(w *runWriter) error) error {
+	f, err := os.CreateTemp(b.dir, "faststringmap-run-")
+	if err != nil {
+		return err
+	}
+	b.runs = append(b.runs, f.Name())
+	w := &runWriter{w: bufio.NewWriter(f)}
+	err = fill(w)
+	if err == nil {
+		err = w.w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Build creates a Uint32Store from the keys and values added so far.
+// It returns an error wrapping ErrDuplicateKey if a key was added more
+// than once.
+func (b *ExternalUint32StoreBuilder) Build() (Uint32Store, error) {
+	var sb sortedBuilder
+	if len(b.runs) == 0 {
+		b.sortEntries()
+		for _, e := range b.entries {
+			if err := sb.add([]byte(e.key), e.value); err != nil {
+				return Uint32Store{}, err
+			}
+		}
+		return sb.finish(), nil
+	}
+	if len(b.entries) > 0 {
+		if err := b.spill(); err != nil {
+			return Uint32Store{}, err
+		}
+	}
+
+	// merge the oldest runs into a new one until few enough are left,
+	// keeping any duplicate keys for the final merge to report
+	for len(b.runs) > mergeFanIn {
+		group := b.runs[:mergeFanIn]
+		err := b.writeRun(func(w *runWriter) error {
+			return mergeRuns(group, func(key []byte, v uint32) error {
+				w.write(string(key), v)
+				return nil
+			})
+		})
+		if err != nil {
+			return Uint32Store{}, err
+		}
+		for _, name := range group {
+			if err := os.Remove(name); err != nil {
+				return Uint32Store{}, err
+			}
+		}
+		b.runs = b.runs[mergeFanIn:]
+	}
+	if err := mergeRuns(b.runs, sb.add); err != nil {
+		return Uint32Store{}, err
+	}
+	return sb.finish(), nil
+}
+
+// mergeRuns calls fn for each entry in the named runs in key order until
+// fn returns an error, which is returned
+func mergeRuns(names []string, fn func(key []byte, v uint32) error) error {
+	h := make(runHeap, 0, len(names))
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rr := &runReader{r: bufio.NewReader(f)}
+		if ok, err := rr.next(); err != nil {
+			return err
+		} else if ok {
+			h = append(h, rr)
+		}
+	}
+	heap.Init(&h)
+	for len(h) > 0 {
+		rr := h[0]
+		if err := fn(rr.key, rr.value); err != nil {
+			return err
+		}
+		if ok, err := rr.next(); err != nil {
+			return err
+		} else if ok {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	return nil
+}
+
+// Close removes the temporary files
+func (b *ExternalUint32StoreBuilder) Close() error {
+	var first error
+	for _, name := range b.runs {
+		if err := os.Remove(name); err != nil && first == nil {
+			first = err
+		}
+	}
+	b.runs = nil
+	return first
+}
+
+// write writes an entry of a run
+func (w *runWriter) write(key string, v uint32) {
+	w.w.Write(w.buf[:binary.PutUvarint(w.buf[:], uint64(len(key)))])
+	w.w.WriteString(key)
+	w.w.Write(w.buf[:binary.PutUvarint(w.buf[:], uint64(v))])
+}
+
+// next reads the next entry of the run, returning false at the end
+func (rr *runReader) next() (bool, error) {
+	n, err := binary.ReadUvarint(rr.r)
+	if err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if cap(rr.key) < int(n) {
+		rr.key = make([]byte, n)
+	}
+	rr.key = rr.key[:n]
+	if _, err := io.ReadFull(rr.r, rr.key); err != nil {
+		return false, fmt.Errorf("reading run: %w", err)
+	}
+	v, err := binary.ReadUvarint(rr.r)
+	if err != nil {
+		return false, fmt.Errorf("reading run: %w", err)
+	}
+	rr.value = uint32(v)
+	return true, nil
+}
+
+func (h runHeap) Len() int            { return len(h) }
+func (h runHeap) Less(i, j int) bool  { return string(h[i].key) < string(h[j].key) }
+func (h runHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x interface{}) { *h = append(*h, x.(*runReader)) }
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
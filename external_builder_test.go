@@ -0,0 +1,79 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestExternalUint32StoreBuilder(t *testing.T) {
+	dir := t.TempDir()
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+
+	b := faststringmap.NewExternalUint32StoreBuilder(dir, 4096)
+	defer b.Close()
+	for _, k := range ms.in {
+		if err := b.Add(k, m[k]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if b.Runs() < 10 {
+		t.Errorf("only %d runs written", b.Runs())
+	}
+	fm, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLookuper(t, "external", &fm, ms)
+	if err := b.Close(); err != nil {
+		t.Error(err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d temporary files left after Close", len(files))
+	}
+
+	// more runs than are merged at once, with a duplicate in the last run
+	b = faststringmap.NewExternalUint32StoreBuilder(dir, 1)
+	ms = mapSliceN(m, 300)
+	for _, k := range ms.in {
+		b.Add(k, m[k])
+	}
+	if b.Runs() != len(ms.in) {
+		t.Errorf("got %d runs want %d", b.Runs(), len(ms.in))
+	}
+	if fm, err = b.Build(); err != nil {
+		t.Fatal(err)
+	}
+	checkLookuper(t, "many runs", &fm, ms)
+	b.Add(ms.in[0], 0)
+	if _, err := b.Build(); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("many runs: got %v want ErrDuplicateKey", err)
+	}
+	b.Close()
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("%d temporary files left after Close", len(files))
+	}
+
+	// duplicates in different runs and in memory
+	for _, limit := range []int{1, 1 << 20} {
+		b = faststringmap.NewExternalUint32StoreBuilder(dir, limit)
+		b.Add("a", 1)
+		b.Add("b", 2)
+		b.Add("a", 3)
+		if _, err := b.Build(); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+			t.Errorf("limit %d: got %v want ErrDuplicateKey", limit, err)
+		}
+		b.Close()
+	}
+
+	b = faststringmap.NewExternalUint32StoreBuilder(dir, 1<<20)
+	fm, err = b.Build()
+	if err != nil || fm.Len() != 0 {
+		t.Errorf("empty: got Len %d, %v", fm.Len(), err)
+	}
+}
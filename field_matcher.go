@@ -0,0 +1,47 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// FieldMatcher matches field names, for example in a hand written JSON
+// decoder, to the position of the name in the list of expected fields.
+// Names are matched directly from the input buffer without conversion to
+// string, and names of a length no field has are rejected without
+// visiting the trie.
+type FieldMatcher struct {
+	m    Uint32Store
+	lens uint64 // bit n set if there is a name of length n, bit 63 for 63 or more
+}
+
+// NewFieldMatcher creates a FieldMatcher for names. If a name
+// occurs more than once then its first position is used.
+func NewFieldMatcher(names ...string) FieldMatcher {
+	var b Uint32StoreBuilder
+	var f FieldMatcher
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			b.Add(name, uint32(i))
+			f.lens |= 1 << lenBit(len(name))
+		}
+	}
+	f.m, _ = b.Build() // no duplicates so no error
+	return f
+}
+
+// MatchField returns the position of name in the names
+// passed to NewFieldMatcher, ok is false if it is not there
+func (f *FieldMatcher) MatchField(name []byte) (id int, ok bool) {
+	if f.lens&(1<<lenBit(len(name))) == 0 {
+		return 0, false
+	}
+	v, ok := f.m.LookupBytes(name)
+	return int(v), ok
+}
+
+func lenBit(n int) uint {
+	if n > 63 {
+		return 63
+	}
+	return uint(n)
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestFieldMatcher(t *testing.T) {
+	long := strings.Repeat("x", 70)
+	f := faststringmap.NewFieldMatcher("id", "name", "tags", "id", "createdAt", long)
+	for _, tc := range []struct {
+		name string
+		id   int
+		ok   bool
+	}{
+		{"id", 0, true},
+		{"name", 1, true},
+		{"tags", 2, true},
+		{"createdAt", 4, true},
+		{long, 5, true},
+		{"", 0, false},
+		{"ID", 0, false},
+		{"nam", 0, false},
+		{"names", 0, false},
+		{long + "x", 0, false},
+	} {
+		if id, ok := f.MatchField([]byte(tc.name)); id != tc.id || ok != tc.ok {
+			t.Errorf("%q: got %d, %v want %d, %v", tc.name, id, ok, tc.id, tc.ok)
+		}
+	}
+}
+
+func BenchmarkFieldMatcher(b *testing.B) {
+	names := []string{"id", "name", "email", "createdAt", "updatedAt", "tags", "owner", "status"}
+	f := faststringmap.NewFieldMatcher(names...)
+	input := make([][]byte, len(names))
+	for i, name := range names {
+		input[i] = []byte(name)
+	}
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for i, name := range input {
+			if id, ok := f.MatchField(name); !ok || id != i {
+				b.Fatalf("ok=%v, id got %d want %d", ok, id, i)
+			}
+		}
+	}
+}
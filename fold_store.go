@@ -0,0 +1,43 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// FoldUint32Store is a fast read only map from string to uint32 which
+// ignores ASCII case in both the keys it is built from and the strings
+// looked up, for example for HTTP header names. Case is folded byte by
+// byte while following the trie, so lookups do not allocate a lower case
+// copy of the string. Bytes outside ASCII are compared exactly.
+// The zero value is an empty map.
+type FoldUint32Store struct {
+	c ByteClassUint32Store // keys in lower case
+}
+
+// asciiLower maps each byte to its ASCII lower case equivalent
+var asciiLower = func() [256]byte {
+	t := IdentityByteClasses()
+	for c := 'A'; c <= 'Z'; c++ {
+		t[c] += 'a' - 'A'
+	}
+	return t
+}()
+
+// NewFoldUint32Store creates from the data supplied in src ignoring ASCII
+// case. If several keys differ only in case then the value is that of the
+// first of them in sorted order.
+func NewFoldUint32Store(src Uint32Source) FoldUint32Store {
+	return FoldUint32Store{c: NewByteClassUint32Store(src, asciiLower)}
+}
+
+// LookupString looks up the supplied string in the map ignoring ASCII case
+func (m *FoldUint32Store) LookupString(s string) (uint32, bool) { return m.c.LookupString(s) }
+
+// LookupBytes looks up the supplied byte slice in the map ignoring ASCII case
+func (m *FoldUint32Store) LookupBytes(s []byte) (uint32, bool) { return m.c.LookupBytes(s) }
+
+// Len returns the number of keys in the map, counting keys which differ
+// only in case once
+func (m *FoldUint32Store) Len() int { return m.c.Len() }
+
+// Walk calls fn for each key in the map, in lower case, in sorted order
+// until fn returns false
+func (m *FoldUint32Store) Walk(fn func(string, uint32) bool) { m.c.Walk(fn) }
@@ -0,0 +1,70 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestFoldUint32Store(t *testing.T) {
+	m := map[string]uint32{
+		"Content-Type":   1,
+		"content-type":   2, // same as Content-Type once folded
+		"Accept":         3,
+		"X-Ünïcode":      4,
+		"ETag":           5,
+		"Content-Length": 6,
+	}
+	fm := faststringmap.NewFoldUint32Store(mapSliceN(m, len(m)))
+	for _, tc := range []struct {
+		key   string
+		value uint32
+		ok    bool
+	}{
+		{"Content-Type", 1, true},
+		{"content-type", 1, true},
+		{"CONTENT-TYPE", 1, true},
+		{"accept", 3, true},
+		{"x-Ünïcode", 4, true},
+		{"x-ünïcode", 0, false}, // only ASCII is folded
+		{"etag", 5, true},
+		{"Content-Length", 6, true},
+		{"Content", 0, false},
+		{"", 0, false},
+	} {
+		if v, ok := fm.LookupString(tc.key); v != tc.value || ok != tc.ok {
+			t.Errorf("LookupString %q: got %d, %v want %d, %v", tc.key, v, ok, tc.value, tc.ok)
+		}
+		if v, ok := fm.LookupBytes([]byte(tc.key)); v != tc.value || ok != tc.ok {
+			t.Errorf("LookupBytes %q: got %d, %v want %d, %v", tc.key, v, ok, tc.value, tc.ok)
+		}
+	}
+	if fm.Len() != 5 {
+		t.Errorf("Len got %d want 5", fm.Len())
+	}
+	var keys []string
+	fm.Walk(func(k string, _ uint32) bool {
+		keys = append(keys, k)
+		return true
+	})
+	want := []string{"accept", "content-length", "content-type", "etag", "x-Ünïcode"}
+	if !equalStrings(keys, want) {
+		t.Errorf("Walk got %q want %q", keys, want)
+	}
+
+	var zero faststringmap.FoldUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+}
+
+func BenchmarkFoldUint32Store(b *testing.B) {
+	ms := typicalCodeStrings(1000)
+	fm := faststringmap.NewFoldUint32Store(ms)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range ms.in {
+			fm.LookupString(k)
+		}
+	}
+}
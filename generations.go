@@ -0,0 +1,107 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNoGeneration is returned when a generation is not held by Generations
+var ErrNoGeneration = errors.New("faststringmap: generation not held")
+
+type (
+	// Generations holds a bounded number of immutable versions of a map,
+	// each identified by an increasing generation number. It is safe for
+	// concurrent use. A reader which obtains a version with Get or Latest
+	// keeps seeing that version for as long as it holds it, even after it
+	// has been dropped from Generations.
+	Generations struct {
+		mu   sync.RWMutex
+		gens []generation // oldest first
+		next uint64       // generation number for next Add
+		keep int          // maximum number of generations held
+	}
+
+	generation struct {
+		id uint64
+		m  Uint32Store
+	}
+)
+
+// NewGenerations creates a Generations which holds at most keep generations
+func NewGenerations(keep int) *Generations {
+	if keep < 1 {
+		keep = 1
+	}
+	return &Generations{keep: keep, next: 1}
+}
+
+// Add adds m as the latest generation, dropping the oldest if more than
+// the retention limit would be held, and returns its generation number
+func (g *Generations) Add(m Uint32Store) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.add(m)
+}
+
+func (g *Generations) add(m Uint32Store) uint64 {
+	id := g.next
+	g.next++
+	g.gens = append(g.gens, generation{id: id, m: m})
+	if n := len(g.gens) - g.keep; n > 0 {
+		// clear dropped entries so they can be garbage collected
+		for i := range g.gens[:n] {
+			g.gens[i] = generation{}
+		}
+		g.gens = g.gens[n:]
+	}
+	return id
+}
+
+// Update adds a new generation derived from the latest one by fn, for
+// example using WithKey or WithoutKey, and returns its generation number.
+// If there are no generations fn is passed an empty map.
+func (g *Generations) Update(fn func(latest *Uint32Store) Uint32Store) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	latest := newUint32StoreSorted(nil, nil)
+	if n := len(g.gens); n > 0 {
+		latest = g.gens[n-1].m
+	}
+	return g.add(fn(&latest))
+}
+
+// Latest returns the newest generation and its number,
+// ok is false if there are no generations
+func (g *Generations) Latest() (m Uint32Store, id uint64, ok bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if n := len(g.gens); n > 0 {
+		return g.gens[n-1].m, g.gens[n-1].id, true
+	}
+	return Uint32Store{}, 0, false
+}
+
+// Get returns generation id if it is still held
+func (g *Generations) Get(id uint64) (Uint32Store, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for i := len(g.gens) - 1; i >= 0; i-- {
+		if g.gens[i].id == id {
+			return g.gens[i].m, true
+		}
+	}
+	return Uint32Store{}, false
+}
+
+// LookupString looks up the supplied string in generation id.
+// The error is ErrNoGeneration if generation id is not held.
+func (g *Generations) LookupString(id uint64, s string) (uint32, bool, error) {
+	m, ok := g.Get(id)
+	if !ok {
+		return 0, false, ErrNoGeneration
+	}
+	v, ok := m.LookupString(s)
+	return v, ok, nil
+}
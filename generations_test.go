@@ -0,0 +1,46 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestGenerations(t *testing.T) {
+	g := faststringmap.NewGenerations(2)
+	if _, _, ok := g.Latest(); ok {
+		t.Error("Latest ok for no generations")
+	}
+
+	id1 := g.Add(faststringmap.NewUint32Store(mapSliceN(map[string]uint32{"a": 1}, 1)))
+	id2 := g.Update(func(m *faststringmap.Uint32Store) faststringmap.Uint32Store { return m.WithKey("b", 2) })
+	id3 := g.Update(func(m *faststringmap.Uint32Store) faststringmap.Uint32Store { return m.WithoutKey("a") })
+
+	if _, ok := g.Get(id1); ok {
+		t.Errorf("generation %d held beyond retention limit", id1)
+	}
+	if _, _, err := g.LookupString(id1, "a"); err != faststringmap.ErrNoGeneration {
+		t.Errorf("got error %v want %v", err, faststringmap.ErrNoGeneration)
+	}
+	for _, tc := range []struct {
+		id    uint64
+		key   string
+		value uint32
+		ok    bool
+	}{
+		{id2, "a", 1, true},
+		{id2, "b", 2, true},
+		{id3, "a", 0, false},
+		{id3, "b", 2, true},
+	} {
+		v, ok, err := g.LookupString(tc.id, tc.key)
+		if err != nil || v != tc.value || ok != tc.ok {
+			t.Errorf("generation %d %q: got %d, %v, %v want %d, %v, nil", tc.id, tc.key, v, ok, err, tc.value, tc.ok)
+		}
+	}
+	if _, id, _ := g.Latest(); id != id3 {
+		t.Errorf("latest generation got %d want %d", id, id3)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "math/bits"
+
+// Lookuper is the read only interface common to the maps from string to
+// uint32 in this package, so code can be written independently of the
+// implementation used
+type Lookuper interface {
+	// LookupString looks up the supplied string in the map
+	LookupString(string) (uint32, bool)
+	// LookupBytes looks up the supplied byte slice in the map
+	LookupBytes([]byte) (uint32, bool)
+	// Len returns the number of keys in the map
+	Len() int
+	// Walk calls fn for each key in the map in sorted order until fn returns false
+	Walk(fn func(string, uint32) bool)
+}
+
+var (
+	_ Lookuper = (*Uint32Store)(nil)
+	_ Lookuper = (*SharedUint32Store)(nil)
+	_ Lookuper = (*SkipBytesStore)(nil)
+	_ Lookuper = PackedMap{}
+	_ Lookuper = (*TwoByteRootStore)(nil)
+	_ Lookuper = (*RadixUint32Store)(nil)
+	_ Lookuper = (*MinimizedUint32Store)(nil)
+	_ Lookuper = (*LOUDSUint32Store)(nil)
+	_ Lookuper = (*MPHUint32Store)(nil)
+	_ Lookuper = (*AdaptiveUint32Store)(nil)
+	_ Lookuper = (*BitmapUint32Store)(nil)
+	_ Lookuper = (*NibbleUint32Store)(nil)
+	_ Lookuper = (*ByteRootStore)(nil)
+	_ Lookuper = (*SplitUint32Store)(nil)
+	_ Lookuper = (*OverlayUint32Store)(nil)
+	_ Lookuper = (*ReloadableUint32Store)(nil)
+	_ Lookuper = (*FoldUint32Store)(nil)
+	_ Lookuper = (*NormalizedUint32Store)(nil)
+	_ Lookuper = (*ByteClassUint32Store)(nil)
+	_ Lookuper = (*AlphabetUint32Store)(nil)
+	_ Lookuper = (*OrderedUint32Store)(nil)
+)
+
+// Len returns the number of keys in the map
+func (m *Uint32Store) Len() int { return m.n }
+
+// Len returns the number of keys in the map
+func (m *SharedUint32Store) Len() int { return len(m.values) + m.extra.Len() }
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (m *SharedUint32Store) Walk(fn func(string, uint32) bool) {
+	if m.keys == nil {
+		return
+	}
+	// merge the vocabulary keys, which are in the same order as their
+	// term numbers, with the extra keys
+	it := m.extra.Iterator()
+	more := it.Next()
+	i := 0
+	for w, word := range m.terms.words {
+		for ; word != 0; word &= word - 1 {
+			k := m.keys.keys[w*64+bits.TrailingZeros64(word)]
+			for ; more && string(it.KeyBytes()) < k; more = it.Next() {
+				if !fn(it.Key(), it.Value()) {
+					return
+				}
+			}
+			if !fn(k, m.values[i]) {
+				return
+			}
+			i++
+		}
+	}
+	for ; more; more = it.Next() {
+		if !fn(it.Key(), it.Value()) {
+			return
+		}
+	}
+}
+
+// Len returns the number of keys in the map
+func (m *SkipBytesStore) Len() int { return m.m.Len() }
+
+// Walk calls fn for each key in the map, with the skipped bytes
+// removed, in sorted order until fn returns false
+func (m *SkipBytesStore) Walk(fn func(string, uint32) bool) { m.m.Walk(fn) }
+
+// Len returns the number of keys in the map
+func (m PackedMap) Len() int {
+	if m.all == nil {
+		return 0
+	}
+	return m.all.countFrom(m.root)
+}
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (m PackedMap) Walk(fn func(string, uint32) bool) {
+	if m.all != nil {
+		m.all.walkFrom(m.root, nil, func(key []byte, v uint32) bool { return fn(string(key), v) })
+	}
+}
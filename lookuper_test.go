@@ -0,0 +1,94 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestLookupers(t *testing.T) {
+	m := randomSmallStrings(500, 6)
+	ms := mapSliceN(m, len(m)/2)
+
+	var keys []string
+	for i, k := range ms.in {
+		if i%2 == 0 {
+			keys = append(keys, k)
+		}
+	}
+	sk := faststringmap.NewSharedKeys(keys)
+
+	fm := faststringmap.NewUint32Store(ms)
+	shared := sk.NewUint32Store(ms)
+	skip := faststringmap.NewSkipBytesStore(ms, "")
+	pm := faststringmap.NewPackedMaps(map[string]faststringmap.Uint32Source{"a": ms, "b": mapSliceN(m, 1)})
+	packed, _ := pm.Map("a")
+
+	for name, l := range map[string]faststringmap.Lookuper{
+		"Uint32Store":       &fm,
+		"SharedUint32Store": &shared,
+		"SkipBytesStore":    &skip,
+		"PackedMap":         packed,
+	} {
+		checkLookuper(t, name, l, ms)
+	}
+}
+
+// checkLookuper checks the Lookuper methods of l give the contents of ms
+func checkLookuper(t *testing.T, name string, l faststringmap.Lookuper, ms mapSlice) {
+	t.Helper()
+	if l.Len() != len(ms.in) {
+		t.Errorf("%s: Len got %d want %d", name, l.Len(), len(ms.in))
+	}
+	for _, k := range ms.in {
+		if v, ok := l.LookupString(k); !ok || v != ms.m[k] {
+			t.Errorf("%s %q: got %d, %v want %d, true", name, k, v, ok, ms.m[k])
+		}
+		if v, ok := l.LookupBytes([]byte(k)); !ok || v != ms.m[k] {
+			t.Errorf("%s %q: got %d, %v want %d, true", name, k, v, ok, ms.m[k])
+		}
+	}
+	for _, k := range ms.out {
+		if v, ok := l.LookupString(k); ok {
+			t.Errorf("%s %q present when not expected, got %d", name, k, v)
+		}
+	}
+
+	want := append([]string(nil), ms.in...)
+	sort.Strings(want)
+	var got []string
+	l.Walk(func(k string, v uint32) bool {
+		if v != ms.m[k] {
+			t.Errorf("%s Walk %q: got %d want %d", name, k, v, ms.m[k])
+		}
+		got = append(got, k)
+		return true
+	})
+	if !equalStrings(got, want) {
+		t.Errorf("%s Walk: got %d keys want %d in sorted order", name, len(got), len(want))
+	}
+
+	n := 0
+	l.Walk(func(string, uint32) bool {
+		n++
+		return n < 3
+	})
+	if want := 3; len(ms.in) >= want && n != want {
+		t.Errorf("%s Walk: stopped after %d keys want %d", name, n, want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
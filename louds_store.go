@@ -0,0 +1,158 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "bytes"
+
+// LOUDSUint32Store is a read only map from string to uint32 holding the
+// trie in a succinct level order unary degree sequence (LOUDS) encoding.
+// Each node takes a byte for its label and about three bits, against 12
+// bytes per node, plus unused slots, in Uint32Store, so it is several
+// times smaller for large key sets with spread out bytes. Lookups are
+// around ten times slower as finding the children of a node needs a
+// select query on the bit sequence.
+// The zero value is an empty map.
+type LOUDSUint32Store struct {
+	// nodes are numbered in breadth first order with the root 0. For each
+	// node louds has a one bit for each child followed by a zero bit.
+	louds    bitVector
+	labels   []byte    // byte leading to each node except the root
+	terminal bitVector // whether each node is a key
+	values   []uint32  // value for each key in node order
+}
+
+// NewLOUDSUint32Store creates from the data supplied in src
+func NewLOUDSUint32Store(src Uint32Source) LOUDSUint32Store {
+	m := NewUint32Store(src)
+	var l LOUDSUint32Store
+	store := m.store
+	queue := []uint32{0}
+	for len(queue) > 0 {
+		bv := &store[queue[0]]
+		queue = queue[1:]
+		l.terminal.push(bv.valid)
+		if bv.valid {
+			l.values = append(l.values, bv.value)
+		}
+		for j := uint32(0); j < uint32(bv.nextLen); j++ {
+			if next := &store[bv.nextLo+j]; next.valid || next.nextLen > 0 {
+				l.louds.push(true)
+				l.labels = append(l.labels, bv.nextOffset+byte(j))
+				queue = append(queue, bv.nextLo+j)
+			}
+		}
+		l.louds.push(false)
+	}
+	l.louds.finish()
+	l.terminal.finish()
+	l.labels = l.labels[:len(l.labels):len(l.labels)]
+	l.values = l.values[:len(l.values):len(l.values)]
+	return l
+}
+
+// children returns the number of the first child of node k and the
+// number of children
+func (l *LOUDSUint32Store) children(k int) (first, n int) {
+	start := 0
+	if k > 0 {
+		start = l.louds.select0(k-1) + 1
+	}
+	// there are k zero bits before start, the rest are children of earlier nodes
+	return start - k + 1, l.louds.onesFrom(start)
+}
+
+// child returns the number of the child of node k for byte b
+func (l *LOUDSUint32Store) child(k int, b byte) (int, bool) {
+	first, n := l.children(k)
+	if j := bytes.IndexByte(l.labels[first-1:first-1+n], b); j >= 0 {
+		return first + j, true
+	}
+	return 0, false
+}
+
+// value returns the value for node k
+func (l *LOUDSUint32Store) value(k int) (uint32, bool) {
+	if !l.terminal.get(k) {
+		return 0, false
+	}
+	return l.values[l.terminal.rank1(k)], true
+}
+
+// LookupString looks up the supplied string in the map
+func (l *LOUDSUint32Store) LookupString(s string) (uint32, bool) {
+	if l.louds.n == 0 {
+		return 0, false
+	}
+	k := 0
+	for i := 0; i < len(s); i++ {
+		var ok bool
+		if k, ok = l.child(k, s[i]); !ok {
+			return 0, false
+		}
+	}
+	return l.value(k)
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (l *LOUDSUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	if l.louds.n == 0 {
+		return 0, false
+	}
+	k := 0
+	for _, b := range s {
+		var ok bool
+		if k, ok = l.child(k, b); !ok {
+			return 0, false
+		}
+	}
+	return l.value(k)
+}
+
+// Len returns the number of keys in the map
+func (l *LOUDSUint32Store) Len() int { return len(l.values) }
+
+// Walk calls fn for each key in the map in sorted order until fn returns
+// false. It uses an explicit stack, like Uint32Store.Walk, so that the
+// length of the keys does not bound the depth of the Go stack.
+func (l *LOUDSUint32Store) Walk(fn func(string, uint32) bool) {
+	if l.louds.n == 0 {
+		return
+	}
+	var frames [walkStackSize]loudsFrame
+	stack := append(frames[:0], loudsFrame{node: 0, next: -1})
+	key := make([]byte, 0, 256)
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.next < 0 {
+			f.first, f.n = l.children(f.node)
+			f.next = 0
+			if v, ok := l.value(f.node); ok && !fn(string(key), v) {
+				return
+			}
+			continue
+		}
+		if f.next < f.n {
+			c := f.first + f.next
+			f.next++
+			stack = append(stack, loudsFrame{node: c, next: -1})
+			key = append(key, l.labels[c-1])
+			continue
+		}
+		if stack = stack[:len(stack)-1]; len(stack) > 0 {
+			key = key[:len(key)-1]
+		}
+	}
+}
+
+// loudsFrame is a node being visited by LOUDSUint32Store.Walk
+type loudsFrame struct {
+	node     int
+	first, n int // first child and number of children, found when node is visited
+	next     int // next child to visit, or -1 for node itself
+}
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the LOUDSUint32Store value itself
+func (l *LOUDSUint32Store) SizeInBytes() int {
+	return l.louds.sizeInBytes() + cap(l.labels) + l.terminal.sizeInBytes() + cap(l.values)*4
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestLOUDSUint32Store(t *testing.T) {
+	m := randomSmallStrings(20000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	l := faststringmap.NewLOUDSUint32Store(ms)
+	checkLookuper(t, "random", &l, ms)
+	fm := faststringmap.NewUint32Store(ms)
+	if l.SizeInBytes()*4 > fm.SizeInBytes() {
+		t.Errorf("LOUDS %d bytes not much smaller than %d", l.SizeInBytes(), fm.SizeInBytes())
+	}
+
+	ms = typicalCodeStrings(1000)
+	ms.out = []string{"", "0", "1000", "-", "-8", "9a"}
+	l = faststringmap.NewLOUDSUint32Store(ms)
+	checkLookuper(t, "codes", &l, ms)
+
+	var zero faststringmap.LOUDSUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	l = faststringmap.NewLOUDSUint32Store(mapSliceN(map[string]uint32{"": 1}, 1))
+	checkLookuper(t, "empty key", &l, mapSliceN(map[string]uint32{"": 1}, 1))
+}
+
+func BenchmarkLOUDSUint32Store(b *testing.B) {
+	m := typicalCodeStrings(nStrsBench)
+	l := faststringmap.NewLOUDSUint32Store(m)
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := l.LookupString(m.in[si])
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
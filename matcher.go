@@ -0,0 +1,106 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// noMatchNode marks the absence of a node in Matcher output links
+const noMatchNode = ^uint32(0)
+
+type (
+	// Matcher finds every occurrence of the keys of a map in a text in a
+	// single pass, using the trie of a Uint32Store with Aho-Corasick
+	// failure links added. The zero value matches nothing.
+	Matcher struct {
+		m     Uint32Store
+		fail  []uint32 // node for the longest proper suffix which is also in the trie
+		out   []uint32 // nearest key node on the failure chain, noMatchNode if none
+		depth []uint32 // number of bytes leading to each node
+	}
+
+	// Match is an occurrence of a key found by Matcher
+	Match struct {
+		Start, End int    // text[Start:End] is the key
+		Value      uint32 // value for the key
+	}
+)
+
+// NewMatcher creates a Matcher for the keys in src. The empty string is
+// never matched even if it is a key.
+func NewMatcher(src Uint32Source) Matcher {
+	mt := Matcher{m: NewUint32Store(src)}
+	store := mt.m.store
+	mt.fail = make([]uint32, len(store))
+	mt.out = make([]uint32, len(store))
+	mt.depth = make([]uint32, len(store))
+	mt.out[0] = noMatchNode
+
+	// breadth first so that the failure links of shorter prefixes are known
+	queue := []uint32{0}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		bv := &store[u]
+		for j := uint32(0); j < uint32(bv.nextLen); j++ {
+			v := bv.nextLo + j
+			if !mt.inTrie(v) {
+				continue
+			}
+			b := bv.nextOffset + byte(j)
+			f := uint32(0)
+			if u != 0 {
+				f = mt.next(mt.fail[u], b)
+			}
+			mt.fail[v] = f
+			if store[f].valid && f != 0 {
+				mt.out[v] = f
+			} else {
+				mt.out[v] = mt.out[f]
+			}
+			mt.depth[v] = mt.depth[u] + 1
+			queue = append(queue, v)
+		}
+	}
+	return mt
+}
+
+// inTrie reports whether store index i is a key or leads to one, rather
+// than being an unused slot in a range of next bytes
+func (mt *Matcher) inTrie(i uint32) bool {
+	bv := &mt.m.store[i]
+	return bv.valid || bv.nextLen > 0
+}
+
+// next returns the node reached from node i on byte b, following failure
+// links until a node with b as a next byte is found or the root is reached
+func (mt *Matcher) next(i uint32, b byte) uint32 {
+	for {
+		if n, ok := mt.m.step(i, b); ok && mt.inTrie(n) {
+			return n
+		}
+		if i == 0 {
+			return 0
+		}
+		i = mt.fail[i]
+	}
+}
+
+// FindAll returns every occurrence of the keys in text, including
+// overlapping ones, ordered by end and then by decreasing length
+func (mt *Matcher) FindAll(text []byte) []Match {
+	if len(mt.m.store) == 0 {
+		return nil
+	}
+	var matches []Match
+	i := uint32(0)
+	for pos, b := range text {
+		i = mt.next(i, b)
+		k := i
+		if !mt.m.store[k].valid || k == 0 {
+			k = mt.out[k]
+		}
+		for ; k != noMatchNode; k = mt.out[k] {
+			end := pos + 1
+			matches = append(matches, Match{Start: end - int(mt.depth[k]), End: end, Value: mt.m.store[k].value})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestMatcher(t *testing.T) {
+	mt := faststringmap.NewMatcher(faststringmap.Uint32MapSource{"he": 1, "she": 2, "his": 3, "hers": 4, "": 5})
+	got := mt.FindAll([]byte("ushers"))
+	want := []faststringmap.Match{{1, 4, 2}, {2, 4, 1}, {2, 6, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	var zero faststringmap.Matcher
+	if got := zero.FindAll([]byte("abc")); got != nil {
+		t.Errorf("zero got %v", got)
+	}
+}
+
+func TestMatcherRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	alphabet := "abc~"
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+	for round := 0; round < 50; round++ {
+		m := map[string]uint32{}
+		for len(m) < 20 {
+			m[randString(1+r.Intn(4))] = uint32(len(m))
+		}
+		text := randString(200)
+		mt := faststringmap.NewMatcher(faststringmap.Uint32MapSource(m))
+		got := mt.FindAll([]byte(text))
+
+		var want []faststringmap.Match
+		for k, v := range m {
+			for i := 0; i+len(k) <= len(text); i++ {
+				if strings.HasPrefix(text[i:], k) {
+					want = append(want, faststringmap.Match{Start: i, End: i + len(k), Value: v})
+				}
+			}
+		}
+		sort.Slice(want, func(i, j int) bool {
+			if want[i].End != want[j].End {
+				return want[i].End < want[j].End
+			}
+			return want[i].Start < want[j].Start
+		})
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("keys %v text %q: got %v want %v", m, text, got, want)
+		}
+	}
+}
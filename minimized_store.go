@@ -0,0 +1,123 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// MinimizedUint32Store is a fast read only map from string to uint32 like
+// Uint32Store except that structurally identical sub-tries with the same
+// values are stored once, making the trie a directed acyclic word graph
+// (DAWG). This greatly reduces the memory used for keys with common
+// endings, such as words or codes with common suffixes, and lookups are
+// as fast as for Uint32Store. It is a separate type because shared nodes
+// cannot be changed individually, as WithKey and WalkRef would do.
+// The zero value is an empty map.
+type MinimizedUint32Store struct {
+	m Uint32Store
+}
+
+// NewMinimizedUint32Store creates from the data supplied in src
+func NewMinimizedUint32Store(src Uint32Source) MinimizedUint32Store {
+	return MinimizeUint32Store(NewUint32Store(src))
+}
+
+// MinimizeUint32Store creates a MinimizedUint32Store with the same
+// contents as m, which is not modified
+func MinimizeUint32Store(m Uint32Store) MinimizedUint32Store {
+	m = m.rooted()
+	if len(m.store) == 0 {
+		return MinimizedUint32Store{}
+	}
+	mn := minimizer{
+		from:   m.store,
+		to:     make([]byteValue, 1, len(m.store)/2+1), // root is always first
+		ranges: make(map[string]uint32),
+	}
+	mn.to[0] = mn.minimize(0)
+	to := make([]byteValue, len(mn.to))
+	copy(to, mn.to)
+	return MinimizedUint32Store{m: Uint32Store{store: to, n: m.n}}
+}
+
+type (
+	// minimizer is used only during MinimizeUint32Store
+	minimizer struct {
+		from   []byteValue
+		to     []byteValue
+		ranges map[string]uint32 // persisted form of a range in to, to its index
+		buf    []byte
+	}
+
+	// minimizeFrame is a node being visited by minimizer.minimize
+	minimizeFrame struct {
+		bv   byteValue   // copy of the node in from
+		next []byteValue // minimized next nodes, up to the one being visited
+	}
+)
+
+// minimize returns the byteValue in the minimized store equivalent to
+// from[i], adding each range of next byteValues to the store unless an
+// identical range is already present. It works up from the last nodes
+// using an explicit stack like walkNodesFrom.
+func (mn *minimizer) minimize(i uint32) byteValue {
+	var frames [walkStackSize]minimizeFrame
+	stack := append(frames[:0], mn.enter(i))
+	for {
+		f := &stack[len(stack)-1]
+		if j := len(f.next); j < int(f.bv.nextLen) {
+			stack = append(stack, mn.enter(f.bv.nextLo+uint32(j)))
+			continue
+		}
+		bv := mn.leave(f)
+		if stack = stack[:len(stack)-1]; len(stack) == 0 {
+			return bv
+		}
+		p := &stack[len(stack)-1]
+		p.next = append(p.next, bv)
+	}
+}
+
+// enter starts visiting from[i]
+func (mn *minimizer) enter(i uint32) minimizeFrame {
+	f := minimizeFrame{bv: mn.from[i]}
+	if f.bv.nextLen > 0 {
+		f.next = make([]byteValue, 0, f.bv.nextLen)
+	}
+	return f
+}
+
+// leave returns the minimized node for f once its next nodes are minimized
+func (mn *minimizer) leave(f *minimizeFrame) byteValue {
+	bv := f.bv
+	if bv.nextLen == 0 {
+		bv.nextLo = 0
+		return bv
+	}
+	mn.buf = mn.buf[:0]
+	for j := range f.next {
+		mn.buf = append(mn.buf, make([]byte, persistNodeSize)...)
+		putNode(mn.buf[j*persistNodeSize:], &f.next[j])
+	}
+	lo, ok := mn.ranges[string(mn.buf)]
+	if !ok {
+		lo = uint32(len(mn.to))
+		mn.to = append(mn.to, f.next...)
+		mn.ranges[string(mn.buf)] = lo
+	}
+	bv.nextLo = lo
+	return bv
+}
+
+// LookupString looks up the supplied string in the map
+func (m *MinimizedUint32Store) LookupString(s string) (uint32, bool) { return m.m.LookupString(s) }
+
+// LookupBytes looks up the supplied byte slice in the map
+func (m *MinimizedUint32Store) LookupBytes(s []byte) (uint32, bool) { return m.m.LookupBytes(s) }
+
+// Len returns the number of keys in the map
+func (m *MinimizedUint32Store) Len() int { return m.m.Len() }
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (m *MinimizedUint32Store) Walk(fn func(string, uint32) bool) { m.m.Walk(fn) }
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the MinimizedUint32Store value itself
+func (m *MinimizedUint32Store) SizeInBytes() int { return m.m.SizeInBytes() }
@@ -0,0 +1,39 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestMinimizedUint32Store(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	mm := faststringmap.NewMinimizedUint32Store(ms)
+	checkLookuper(t, "random", &mm, ms)
+
+	// every combination of prefix and suffix, with the value depending only
+	// on the suffix, so the sub-tries after each prefix are identical
+	codes := map[string]uint32{}
+	for p := 0; p < 100; p++ {
+		for s := 0; s < 100; s++ {
+			codes["P"+strconv.Itoa(p)+"-S"+strconv.Itoa(s)] = uint32(s)
+		}
+	}
+	ms = mapSliceN(codes, len(codes))
+	ms.out = []string{"", "P", "P1", "P1-", "P1-S", "P1-S100", "P100-S1", "P1-S1-"}
+	full := faststringmap.NewUint32Store(ms)
+	mm = faststringmap.MinimizeUint32Store(full)
+	checkLookuper(t, "codes", &mm, ms)
+	if mm.SizeInBytes()*10 > full.SizeInBytes() {
+		t.Errorf("minimized %d bytes not much smaller than %d", mm.SizeInBytes(), full.SizeInBytes())
+	}
+
+	var zero faststringmap.MinimizedUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	mm = faststringmap.NewMinimizedUint32Store(mapSlice{})
+	checkLookuper(t, "empty", &mm, mapSlice{out: []string{"", "a"}})
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"sort"
+	"sync"
+)
+
+// MissRecorder wraps a Lookuper and records the distinct keys which were
+// not found, up to a limit, so that they can be reported periodically.
+// It is safe for concurrent use if the wrapped Lookuper is.
+type MissRecorder struct {
+	l   Lookuper
+	max int
+
+	mu      sync.Mutex
+	misses  map[string]struct{}
+	dropped int // misses not recorded because of the limit
+}
+
+var _ Lookuper = (*MissRecorder)(nil)
+
+// NewMissRecorder creates a MissRecorder for l recording at most max distinct missing keys
+func NewMissRecorder(l Lookuper, max int) *MissRecorder {
+	return &MissRecorder{l: l, max: max, misses: make(map[string]struct{})}
+}
+
+// LookupString looks up the supplied string, recording it if it is not found
+func (r *MissRecorder) LookupString(s string) (uint32, bool) {
+	v, ok := r.l.LookupString(s)
+	if !ok {
+		r.mu.Lock()
+		if _, seen := r.misses[s]; !seen {
+			r.record(s)
+		}
+		r.mu.Unlock()
+	}
+	return v, ok
+}
+
+// LookupBytes looks up the supplied byte slice, recording it if it is not found
+func (r *MissRecorder) LookupBytes(s []byte) (uint32, bool) {
+	v, ok := r.l.LookupBytes(s)
+	if !ok {
+		r.mu.Lock()
+		if _, seen := r.misses[string(s)]; !seen {
+			r.record(string(s))
+		}
+		r.mu.Unlock()
+	}
+	return v, ok
+}
+
+// record adds a key which has not been seen before, r.mu must be held
+func (r *MissRecorder) record(s string) {
+	if len(r.misses) < r.max {
+		r.misses[s] = struct{}{}
+	} else {
+		r.dropped++
+	}
+}
+
+// Len returns the number of keys in the wrapped map
+func (r *MissRecorder) Len() int { return r.l.Len() }
+
+// Walk calls fn for each key in the wrapped map in sorted order until fn returns false
+func (r *MissRecorder) Walk(fn func(string, uint32) bool) { r.l.Walk(fn) }
+
+// Misses returns the distinct missing keys recorded so far in sorted order
+func (r *MissRecorder) Misses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sortedMisses()
+}
+
+// Drain returns the distinct missing keys recorded so far in sorted order
+// and the number of further misses which were not recorded because of the
+// limit, then starts recording afresh
+func (r *MissRecorder) Drain() (misses []string, dropped int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	misses, dropped = r.sortedMisses(), r.dropped
+	r.misses = make(map[string]struct{})
+	r.dropped = 0
+	return misses, dropped
+}
+
+func (r *MissRecorder) sortedMisses() []string {
+	a := make([]string, 0, len(r.misses))
+	for k := range r.misses {
+		a = append(a, k)
+	}
+	sort.Strings(a)
+	return a
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestMissRecorder(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{"a": 1, "b": 2})
+	r := faststringmap.NewMissRecorder(&fm, 3)
+
+	if v, ok := r.LookupString("a"); !ok || v != 1 {
+		t.Errorf("a: got %d, %v want 1, true", v, ok)
+	}
+	for _, k := range []string{"x", "c", "x", "d"} {
+		r.LookupString(k)
+	}
+	r.LookupBytes([]byte("c"))
+	r.LookupBytes([]byte("b"))
+	if got, want := r.Misses(), []string{"c", "d", "x"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Misses got %q want %q", got, want)
+	}
+
+	r.LookupBytes([]byte("e"))
+	r.LookupString("f")
+	r.LookupString("x")
+	misses, dropped := r.Drain()
+	if want := []string{"c", "d", "x"}; !reflect.DeepEqual(misses, want) || dropped != 2 {
+		t.Errorf("Drain got %q, %d want %q, 2", misses, dropped, want)
+	}
+
+	r.LookupString("e")
+	misses, dropped = r.Drain()
+	if want := []string{"e"}; !reflect.DeepEqual(misses, want) || dropped != 0 {
+		t.Errorf("Drain got %q, %d want %q, 0", misses, dropped, want)
+	}
+	if r.Len() != 2 {
+		t.Errorf("Len got %d want 2", r.Len())
+	}
+}
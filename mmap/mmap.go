@@ -0,0 +1,114 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+// Package mmap provides read only memory mapping of files, with Unix
+// and Windows implementations, for serving persisted maps without
+// copying them into the Go heap. On other platforms the file is read
+// into memory instead.
+package mmap
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrClosed is returned when using a Map after Close
+var ErrClosed = errors.New("mmap: closed")
+
+// Map is a read only memory mapped file. ReadAt and Len may be called
+// concurrently with each other and with Close.
+type Map struct {
+	mu     sync.RWMutex // held for reading while data is used, for writing to unmap it
+	data   []byte
+	handle uintptr // platform specific mapping handle
+	closed bool
+}
+
+var _ io.ReaderAt = (*Map)(nil)
+
+// Open maps the named file into memory
+func Open(name string) (*Map, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return OpenFile(f)
+}
+
+// OpenFile maps the whole of f into memory. f may be closed
+// once OpenFile returns without affecting the Map.
+func OpenFile(f *os.File) (*Map, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if size != int64(int(size)) {
+		return nil, errors.New("mmap: file too large")
+	}
+	m := &Map{}
+	if size == 0 {
+		// mapping an empty file is an error on some platforms
+		return m, nil
+	}
+	if err := m.mmap(f, int(size)); err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: f.Name(), Err: err}
+	}
+	return m, nil
+}
+
+// Bytes returns the contents of the file. The slice must not be
+// modified and must not be used after Close, which is not prevented:
+// the caller must ensure Close is not called while it is in use.
+func (m *Map) Bytes() []byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data
+}
+
+// Len returns the length of the file, or zero after Close
+func (m *Map) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.data)
+}
+
+// ReadAt implements io.ReaderAt. It returns ErrClosed after Close and
+// Close waits for any ReadAt in progress to finish before unmapping.
+func (m *Map) ReadAt(p []byte, off int64) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return 0, ErrClosed
+	}
+	if off < 0 {
+		return 0, errors.New("mmap: negative offset")
+	}
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close unmaps the file. Any slice returned by Bytes must no longer be
+// used. Calling Close more than once returns ErrClosed.
+func (m *Map) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	m.closed = true
+	if m.data == nil {
+		return nil
+	}
+	err := m.munmap()
+	m.data = nil
+	return err
+}
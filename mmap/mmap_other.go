@@ -0,0 +1,23 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package mmap
+
+import (
+	"io"
+	"os"
+)
+
+// mmap reads the file into memory where memory mapping is not available
+func (m *Map) mmap(f *os.File, size int) error {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return err
+	}
+	m.data = data
+	return nil
+}
+
+func (m *Map) munmap() error { return nil }
@@ -0,0 +1,125 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package mmap_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+	"github.com/sensiblecodeio/faststringmap/mmap"
+)
+
+func TestMap(t *testing.T) {
+	dir := t.TempDir()
+	want := bytes.Repeat([]byte("0123456789"), 1000)
+	name := filepath.Join(dir, "data")
+	if err := os.WriteFile(name, want, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := mmap.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m.Bytes(), want) || m.Len() != len(want) {
+		t.Error("mapped contents differ from file")
+	}
+
+	p := make([]byte, 4)
+	if n, err := m.ReadAt(p, 12); n != 4 || err != nil || string(p) != "2345" {
+		t.Errorf("ReadAt got %d, %v, %q want 4, nil, \"2345\"", n, err, p)
+	}
+	if n, err := m.ReadAt(p, int64(len(want)-2)); n != 2 || err != io.EOF {
+		t.Errorf("ReadAt at end got %d, %v want 2, EOF", n, err)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != mmap.ErrClosed {
+		t.Errorf("second Close got %v want %v", err, mmap.ErrClosed)
+	}
+	if _, err := m.ReadAt(p, 0); err != mmap.ErrClosed {
+		t.Errorf("ReadAt after Close got %v want %v", err, mmap.ErrClosed)
+	}
+}
+
+func TestMapCloseDuringReadAt(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(name, bytes.Repeat([]byte("x"), 1<<16), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := mmap.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := make([]byte, 1024)
+			for {
+				n, err := m.ReadAt(p, 100)
+				if err == mmap.ErrClosed {
+					return
+				}
+				if n != len(p) || err != nil || p[0] != 'x' {
+					t.Errorf("ReadAt got %d, %v, %q", n, err, p[0])
+					return
+				}
+			}
+		}()
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if m.Len() != 0 {
+		t.Errorf("Len after Close got %d want 0", m.Len())
+	}
+}
+
+func TestMapEmpty(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(name, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := mmap.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 0 {
+		t.Errorf("Len got %d want 0", m.Len())
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMapReaderAtStore(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{"key1": 42, "key2": 27644437, "l": 2})
+	b, _ := fm.MarshalBinary()
+	name := filepath.Join(t.TempDir(), "map")
+	if err := os.WriteFile(name, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := mmap.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	rs, err := faststringmap.OpenReaderAt(m, int64(m.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := rs.LookupString("key2"); v != 27644437 || !ok || err != nil {
+		t.Errorf("got %d, %v, %v want 27644437, true, nil", v, ok, err)
+	}
+}
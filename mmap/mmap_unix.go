@@ -0,0 +1,24 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package mmap
+
+import (
+	"os"
+	"syscall"
+)
+
+func (m *Map) mmap(f *os.File, size int) error {
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+	m.data = data
+	return nil
+}
+
+func (m *Map) munmap() error {
+	return syscall.Munmap(m.data)
+}
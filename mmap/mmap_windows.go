@@ -0,0 +1,39 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package mmap
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func (m *Map) mmap(f *os.File, size int) error {
+	h, err := syscall.CreateFileMapping(syscall.Handle(f.Fd()), nil, syscall.PAGE_READONLY, 0, 0, nil)
+	if err != nil {
+		return err
+	}
+	addr, err := syscall.MapViewOfFile(h, syscall.FILE_MAP_READ, 0, 0, uintptr(size))
+	if err != nil {
+		syscall.CloseHandle(h)
+		return err
+	}
+	m.handle = uintptr(h)
+	// reinterpret addr rather than convert it, which vet reports as a
+	// misuse of unsafe.Pointer, as the mapping is not Go memory
+	p := *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+	m.data = (*[maxMapSize]byte)(p)[:size:size]
+	return nil
+}
+
+// maxMapSize is the length of an array type large enough for any file
+// which can be mapped: 1<<47-1 on 64-bit platforms and 1<<31-1 on 32-bit ones
+const maxMapSize = 1<<(31+16*(^uint(0)>>63)) - 1
+
+func (m *Map) munmap() error {
+	err := syscall.UnmapViewOfFile(uintptr(unsafe.Pointer(&m.data[0])))
+	if cerr := syscall.CloseHandle(syscall.Handle(m.handle)); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"unsafe"
+)
+
+const (
+	mphBucketSize  = 4       // average number of keys per bucket
+	mphMaxDisplace = 1 << 22 // displacements tried for a bucket before choosing a new seed
+)
+
+// MPHUint32Store is a read only map from string to uint32 using a minimal
+// perfect hash of the keys, so a lookup hashes the string once and
+// compares it with the single key which could match, whatever the length
+// of the keys. This is much faster than a trie for long keys such as UUIDs
+// or URLs. The keys are stored in full to reject strings not in the map.
+// The zero value is an empty map.
+type MPHUint32Store struct {
+	seed   uint64
+	disp   []uint32 // displacement for each bucket of keys
+	offs   []uint32 // start of each key in keys, plus the end of the last
+	keys   string   // the keys in slot order
+	values []uint32 // the values in slot order
+	sorted []uint32 // the slots in key order, for Walk
+}
+
+// MapMPH is another name for MPHUint32Store
+type MapMPH = MPHUint32Store
+
+// NewMPHUint32Store creates from the data supplied in src using the
+// hash and displace method: the keys are divided into buckets which, largest
+// first, are given the first displacement of the hash which places all their
+// keys in free slots. Equal keys always hash to the same slot, so it panics
+// with an error wrapping ErrDuplicateKey if src has the same key twice,
+// and with one wrapping ErrTooLarge if the keys total more than
+// math.MaxUint32 bytes.
+func NewMPHUint32Store(src Uint32Source) MPHUint32Store {
+	keys := src.AppendKeys([]string(nil))
+	if len(keys) == 0 {
+		return MPHUint32Store{}
+	}
+	sort.Strings(keys)
+	for i := 1; i < len(keys); i++ {
+		if keys[i] == keys[i-1] {
+			panic(fmt.Errorf("%w: %q", ErrDuplicateKey, keys[i]))
+		}
+	}
+	n := uint64(len(keys))
+	nb := (n + mphBucketSize - 1) / mphBucketSize
+	hashes := make([]uint64, n)
+	slots := make([]int, len(keys)) // slot for each key
+	taken := make([]bool, n)
+	seed := uint64(0)
+retry:
+	m := MPHUint32Store{seed: seed, disp: make([]uint32, nb)}
+	seed++
+	buckets := make([][]int, nb) // indices in keys
+	for i, k := range keys {
+		hashes[i] = mphHashString(k, m.seed)
+		b, _ := bits.Mul64(hashes[i], nb)
+		buckets[b] = append(buckets[b], i)
+	}
+	order := make([]int, nb)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return len(buckets[order[i]]) > len(buckets[order[j]]) })
+	for i := range taken {
+		taken[i] = false
+	}
+	for _, b := range order {
+		bucket := buckets[b]
+		if len(bucket) == 0 {
+			break
+		}
+	displace:
+		for d := uint32(0); ; d++ {
+			if d == mphMaxDisplace {
+				goto retry // hashes of keys are unlucky
+			}
+			for bi, i := range bucket {
+				s := mphSlot(hashes[i], d, n)
+				if taken[s] {
+					for _, j := range bucket[:bi] {
+						taken[slots[j]] = false
+					}
+					continue displace
+				}
+				taken[s] = true
+				slots[i] = int(s)
+			}
+			m.disp[b] = d
+			break
+		}
+	}
+
+	byslot := make([]int, n)
+	size := uint64(0)
+	m.sorted = make([]uint32, n)
+	for i, s := range slots {
+		byslot[s] = i
+		m.sorted[i] = uint32(s)
+		size += uint64(len(keys[i]))
+	}
+	if size > math.MaxUint32 {
+		panic(fmt.Errorf("%w: keys total more than %d bytes", ErrTooLarge, uint64(math.MaxUint32)))
+	}
+	kb := make([]byte, 0, size)
+	m.offs = make([]uint32, 0, n+1)
+	m.values = make([]uint32, n)
+	for s, i := range byslot {
+		m.offs = append(m.offs, uint32(len(kb)))
+		kb = append(kb, keys[i]...)
+		m.values[s] = src.Get(keys[i])
+	}
+	m.offs = append(m.offs, uint32(len(kb)))
+	m.keys = string(kb)
+	return m
+}
+
+// mphSlot returns the slot for a key with hash h and displacement d in a
+// table of n slots
+func mphSlot(h uint64, d uint32, n uint64) uint64 {
+	// splitmix64 finaliser of h offset by the displacement
+	x := h + uint64(d+1)*0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	x ^= x >> 31
+	s, _ := bits.Mul64(x, n)
+	return s
+}
+
+// mphHashString returns the hash of s with the given seed, taking the
+// bytes eight at a time
+func mphHashString(s string, seed uint64) uint64 {
+	h := seed ^ uint64(len(s))*0xa0761d6478bd642f
+	for ; len(s) >= 8; s = s[8:] {
+		v := uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+			uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+		h = mum(h^v, 0xe7037ed1a0b428db)
+	}
+	v := uint64(0)
+	for i := 0; i < len(s); i++ {
+		v |= uint64(s[i]) << uint(8*i)
+	}
+	return mum(h^v, 0x8ebc6af09c88c6e3)
+}
+
+// mum mixes a and b by folding their 128 bit product
+func mum(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return hi ^ lo
+}
+
+// slot returns the only slot in which the key with hash h can be
+func (m *MPHUint32Store) slot(h uint64) uint64 {
+	b, _ := bits.Mul64(h, uint64(len(m.disp)))
+	return mphSlot(h, m.disp[b], uint64(len(m.values)))
+}
+
+// LookupString looks up the supplied string in the map
+func (m *MPHUint32Store) LookupString(s string) (uint32, bool) {
+	if len(m.values) == 0 {
+		return 0, false
+	}
+	i := m.slot(mphHashString(s, m.seed))
+	if m.keys[m.offs[i]:m.offs[i+1]] != s {
+		return 0, false
+	}
+	return m.values[i], true
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (m *MPHUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	if len(m.values) == 0 {
+		return 0, false
+	}
+	// s is only read while hashing so it need not be copied to a string
+	i := m.slot(mphHashString(*(*string)(unsafe.Pointer(&s)), m.seed))
+	if m.keys[m.offs[i]:m.offs[i+1]] != string(s) {
+		return 0, false
+	}
+	return m.values[i], true
+}
+
+// Len returns the number of keys in the map
+func (m *MPHUint32Store) Len() int { return len(m.values) }
+
+// Walk calls fn for each key in the map in sorted order until fn returns
+// false
+func (m *MPHUint32Store) Walk(fn func(string, uint32) bool) {
+	for _, i := range m.sorted {
+		if !fn(m.keys[m.offs[i]:m.offs[i+1]], m.values[i]) {
+			return
+		}
+	}
+}
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the MPHUint32Store value itself
+func (m *MPHUint32Store) SizeInBytes() int {
+	return cap(m.disp)*4 + cap(m.offs)*4 + len(m.keys) + cap(m.values)*4 + cap(m.sorted)*4
+}
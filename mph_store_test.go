@@ -0,0 +1,62 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestMPHUint32Store(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 100, 20000} {
+		m := randomSmallStrings(n, 8)
+		ms := mapSliceN(m, len(m)/2)
+		mm := faststringmap.NewMPHUint32Store(ms)
+		checkLookuper(t, "random", &mm, ms)
+	}
+
+	ms := uuidStrings(5000)
+	ms.out = []string{"", "0", ms.in[0][:35], ms.in[0] + "0"}
+	mm := faststringmap.NewMPHUint32Store(ms)
+	checkLookuper(t, "uuid", &mm, ms)
+
+	var zero faststringmap.MPHUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	mm = faststringmap.NewMPHUint32Store(mapSlice{})
+	checkLookuper(t, "empty", &mm, mapSlice{out: []string{"", "a"}})
+}
+
+func TestMPHUint32StoreDuplicateKey(t *testing.T) {
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+			t.Errorf("got panic %v want %v", err, faststringmap.ErrDuplicateKey)
+		}
+	}()
+	faststringmap.NewMPHUint32Store(mapSlice{m: map[string]uint32{"a": 1, "b": 2}, in: []string{"a", "b", "a"}})
+}
+
+func TestMPHUint32StoreWalkAllocs(t *testing.T) {
+	m := faststringmap.NewMPHUint32Store(uuidStrings(1000))
+	n := testing.AllocsPerRun(10, func() {
+		m.Walk(func(string, uint32) bool { return true })
+	})
+	if n != 0 {
+		t.Errorf("Walk made %v allocations, want 0", n)
+	}
+}
+
+func BenchmarkMPHUint32StoreUUID(b *testing.B) {
+	m := uuidStrings(nStrsBench)
+	fm := faststringmap.NewMPHUint32Store(m)
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := fm.LookupString(m.in[si])
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
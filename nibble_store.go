@@ -0,0 +1,88 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// NibbleUint32Store is a fast read only map from string to uint32 like
+// Uint32Store except that each byte is followed as two steps, one for each
+// 4 bit half, so no node has a range of more than 16 next values. This
+// bounds the unused slots for keys whose bytes are widely spread, at the
+// cost of twice as many steps for each lookup.
+// The zero value is an empty map.
+type NibbleUint32Store struct {
+	m Uint32Store // keys with each byte split into two nibbles
+}
+
+// NewNibbleUint32Store creates from the data supplied in src
+func NewNibbleUint32Store(src Uint32Source) NibbleUint32Store {
+	keys := src.AppendKeys([]string(nil))
+	split := make(map[string]string, len(keys)) // split key to original key
+	for i, k := range keys {
+		b := make([]byte, 0, 2*len(k))
+		for j := 0; j < len(k); j++ {
+			b = append(b, k[j]>>4, k[j]&0xf)
+		}
+		keys[i] = string(b)
+		split[keys[i]] = k
+	}
+	return NibbleUint32Store{m: NewUint32Store(funcSource{
+		keys: keys,
+		get:  func(s string) uint32 { return src.Get(split[s]) },
+	})}
+}
+
+// LookupString looks up the supplied string in the map
+func (n *NibbleUint32Store) LookupString(s string) (uint32, bool) {
+	if len(n.m.store) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	for j := 0; j < len(s); j++ {
+		var ok bool
+		if i, ok = n.m.step(i, s[j]>>4); !ok {
+			return 0, false
+		}
+		if i, ok = n.m.step(i, s[j]&0xf); !ok {
+			return 0, false
+		}
+	}
+	bv := &n.m.store[i]
+	return bv.value, bv.valid
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (n *NibbleUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	if len(n.m.store) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	for _, b := range s {
+		var ok bool
+		if i, ok = n.m.step(i, b>>4); !ok {
+			return 0, false
+		}
+		if i, ok = n.m.step(i, b&0xf); !ok {
+			return 0, false
+		}
+	}
+	bv := &n.m.store[i]
+	return bv.value, bv.valid
+}
+
+// Len returns the number of keys in the map
+func (n *NibbleUint32Store) Len() int { return n.m.Len() }
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (n *NibbleUint32Store) Walk(fn func(string, uint32) bool) {
+	var key []byte
+	n.m.walk(func(split []byte, v uint32) bool {
+		key = key[:0]
+		for j := 0; j+1 < len(split); j += 2 {
+			key = append(key, split[j]<<4|split[j+1])
+		}
+		return fn(string(key), v)
+	})
+}
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the NibbleUint32Store value itself
+func (n *NibbleUint32Store) SizeInBytes() int { return n.m.SizeInBytes() }
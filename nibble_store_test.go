@@ -0,0 +1,33 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNibbleUint32Store(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	nm := faststringmap.NewNibbleUint32Store(ms)
+	checkLookuper(t, "random", &nm, ms)
+
+	// bytes spread across the whole range
+	spread := map[string]uint32{}
+	for i := 0; i < 256; i += 17 {
+		spread[string([]byte{byte(i), byte(255 - i)})] = uint32(i)
+	}
+	ms = mapSliceN(spread, len(spread))
+	ms.out = []string{"", "\x00", "\x00\x00", "\x11\xef", "\xff\x00\x00"}
+	nm = faststringmap.NewNibbleUint32Store(ms)
+	checkLookuper(t, "spread", &nm, ms)
+	fm := faststringmap.NewUint32Store(ms)
+	if nm.SizeInBytes()*4 > fm.SizeInBytes() {
+		t.Errorf("nibble %d bytes not much smaller than %d", nm.SizeInBytes(), fm.SizeInBytes())
+	}
+
+	var zero faststringmap.NibbleUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CollisionPolicy says what to do when several keys are the same once normalized
+type CollisionPolicy int
+
+const (
+	// CollisionFirst uses the value of the first key in sorted order
+	CollisionFirst CollisionPolicy = iota
+	// CollisionLast uses the value of the last key in sorted order
+	CollisionLast
+	// CollisionError fails with an error wrapping ErrDuplicateKey
+	CollisionError
+)
+
+// NormalizedUint32Store is a read only map from string to uint32 which
+// applies a normalizing function to the keys it is built from and to the
+// strings looked up, so that for example Unicode keys which differ only
+// in case or composition find the same entry. Unlike FoldUint32Store
+// each lookup normalizes the whole string first, which may allocate.
+// The zero value is an empty map.
+type NormalizedUint32Store struct {
+	m         Uint32Store // normalized keys
+	normalize func(string) string
+}
+
+// NewNormalizedUint32Store creates from the data supplied in src with the
+// keys normalized by normalize, using policy for keys which are the same
+// once normalized. The standard library has no Unicode normalization, so
+// for NFC pass a function such as norm.NFC.String from
+// golang.org/x/text/unicode/norm, combined with FoldCase if required.
+func NewNormalizedUint32Store(src Uint32Source, normalize func(string) string, policy CollisionPolicy) (NormalizedUint32Store, error) {
+	keys := src.AppendKeys([]string(nil))
+	sort.Strings(keys)
+	orig := make(map[string]string, len(keys)) // normalized key to original key
+	normalized := make([]string, 0, len(keys))
+	for _, k := range keys {
+		nk := normalize(k)
+		if prev, ok := orig[nk]; ok {
+			switch policy {
+			case CollisionError:
+				return NormalizedUint32Store{}, fmt.Errorf("%w: %q and %q both normalize to %q", ErrDuplicateKey, prev, k, nk)
+			case CollisionLast:
+				orig[nk] = k
+			}
+			continue
+		}
+		orig[nk] = k
+		normalized = append(normalized, nk)
+	}
+	return NormalizedUint32Store{
+		m: NewUint32Store(funcSource{
+			keys: normalized,
+			get:  func(nk string) uint32 { return src.Get(orig[nk]) },
+		}),
+		normalize: normalize,
+	}, nil
+}
+
+// LookupString looks up the supplied string in the map after normalizing it
+func (m *NormalizedUint32Store) LookupString(s string) (uint32, bool) {
+	if m.normalize == nil {
+		return 0, false
+	}
+	return m.m.LookupString(m.normalize(s))
+}
+
+// LookupBytes looks up the supplied byte slice in the map after normalizing it
+func (m *NormalizedUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	if m.normalize == nil {
+		return 0, false
+	}
+	return m.m.LookupString(m.normalize(string(s)))
+}
+
+// Len returns the number of keys in the map, counting keys which are the
+// same once normalized once
+func (m *NormalizedUint32Store) Len() int { return m.m.Len() }
+
+// Walk calls fn for each normalized key in the map in sorted order until
+// fn returns false
+func (m *NormalizedUint32Store) Walk(fn func(string, uint32) bool) { m.m.Walk(fn) }
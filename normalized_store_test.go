@@ -0,0 +1,66 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNormalizedUint32Store(t *testing.T) {
+	m := map[string]uint32{
+		"Straße":  1,
+		"STRASSE": 2, // same as Straße once fully folded
+		"Größe":   3,
+		"größe":   4, // same as Größe once folded
+		"Kelvin":  5,
+		"ﬁle":     6, // with the fi ligature
+	}
+	src := mapSliceN(m, len(m))
+	for _, tc := range []struct {
+		policy  faststringmap.CollisionPolicy
+		strasse uint32
+		größe   uint32
+	}{
+		{faststringmap.CollisionFirst, 2, 3},
+		{faststringmap.CollisionLast, 1, 4},
+	} {
+		nm, err := faststringmap.NewNormalizedUint32Store(src, faststringmap.FoldCase, tc.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range []struct {
+			key   string
+			value uint32
+			ok    bool
+		}{
+			{"straße", tc.strasse, true},
+			{"STRAẞE", tc.strasse, true}, // capital sharp s
+			{"strasse", tc.strasse, true},
+			{"GRÖßE", tc.größe, true},
+			{"grösse", tc.größe, true},
+			{"\u212aelvin", 5, true}, // Kelvin sign
+			{"kelvins", 0, false},
+			{"FILE", 6, true},
+		} {
+			if v, ok := nm.LookupString(c.key); v != c.value || ok != c.ok {
+				t.Errorf("policy %d LookupString %q: got %d, %v want %d, %v", tc.policy, c.key, v, ok, c.value, c.ok)
+			}
+			if v, ok := nm.LookupBytes([]byte(c.key)); v != c.value || ok != c.ok {
+				t.Errorf("policy %d LookupBytes %q: got %d, %v want %d, %v", tc.policy, c.key, v, ok, c.value, c.ok)
+			}
+		}
+		if nm.Len() != 4 {
+			t.Errorf("policy %d Len got %d want 4", tc.policy, nm.Len())
+		}
+	}
+
+	if _, err := faststringmap.NewNormalizedUint32Store(src, faststringmap.FoldCase, faststringmap.CollisionError); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("CollisionError: got %v want ErrDuplicateKey", err)
+	}
+
+	var zero faststringmap.NormalizedUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+}
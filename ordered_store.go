@@ -0,0 +1,146 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+// OrderedUint32Store is a fast read only map from string to uint32 which
+// also answers order statistics queries: the number of keys before a
+// string, and the key at a position in sorted order. It holds a
+// Uint32Store and, for every node, the number of keys in the sub-trie
+// below it and the number of keys before it, computed when it is created.
+// The zero value is an empty map.
+type OrderedUint32Store struct {
+	m      Uint32Store
+	count  []uint32 // number of keys in the sub-trie from each node
+	before []uint32 // number of keys before the byte sequence leading to each node
+}
+
+// NewOrderedUint32Store creates from the data supplied in src
+func NewOrderedUint32Store(src Uint32Source) OrderedUint32Store {
+	return OrderUint32Store(NewUint32Store(src))
+}
+
+// OrderUint32Store creates an OrderedUint32Store with the same contents
+// as m, which must not be changed afterwards, for example by WalkRef
+func OrderUint32Store(m Uint32Store) OrderedUint32Store {
+	m = m.rooted()
+	if len(m.store) == 0 {
+		return OrderedUint32Store{}
+	}
+	o := OrderedUint32Store{
+		m:      m,
+		count:  make([]uint32, len(m.store)),
+		before: make([]uint32, len(m.store)),
+	}
+	o.setCounts(0)
+	return o
+}
+
+// setCounts sets count and before for node i and the nodes below it,
+// where there are no keys before node i, using an explicit stack
+func (o *OrderedUint32Store) setCounts(i uint32) {
+	var frames [walkStackSize]sumFrame
+	stack := append(frames[:0], sumFrame{node: i})
+	o.before[i] = 0
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		bv := &o.m.store[f.node]
+		n := f.sum // keys in the next nodes visited so far
+		if bv.valid {
+			n++
+		}
+		if f.next < int(bv.nextLen) {
+			c := bv.nextLo + uint32(f.next)
+			f.next++
+			o.before[c] = o.before[f.node] + n
+			stack = append(stack, sumFrame{node: c})
+			continue
+		}
+		o.count[f.node] = n
+		if stack = stack[:len(stack)-1]; len(stack) > 0 {
+			stack[len(stack)-1].sum += n
+		}
+	}
+}
+
+// Rank returns the number of keys in the map less than s, which need not
+// be in the map, so it is the position of s in sorted order if it is
+func (o *OrderedUint32Store) Rank(s string) int {
+	store := o.m.store
+	if len(store) == 0 {
+		return 0
+	}
+	i := uint32(0)
+	for d := 0; d < len(s); d++ {
+		bv := &store[i]
+		c := s[d]
+		if c < bv.nextOffset || bv.nextLen == 0 {
+			// s is after the key for i, if any, and before the keys below
+			if bv.valid {
+				return int(o.before[i]) + 1
+			}
+			return int(o.before[i])
+		}
+		if uint16(c-bv.nextOffset) >= bv.nextLen {
+			// s is after every key in the sub-trie
+			return int(o.before[i] + o.count[i])
+		}
+		i = bv.nextLo + uint32(c-bv.nextOffset)
+	}
+	return int(o.before[i])
+}
+
+// Select returns the key at position r in sorted order, counting from 0,
+// and its value. ok is false if r is not less than Len.
+func (o *OrderedUint32Store) Select(r int) (key string, v uint32, ok bool) {
+	if r < 0 || r >= o.Len() {
+		return "", 0, false
+	}
+	store := o.m.store
+	target := uint32(r)
+	buf := make([]byte, 0, 64)
+	i := uint32(0)
+	for {
+		bv := &store[i]
+		if bv.valid && o.before[i] == target {
+			return string(buf), bv.value, true
+		}
+		// the last next node with no more than target keys before it,
+		// which skips empty nodes as they have the same count as the next
+		lo, n := bv.nextLo, int(bv.nextLen)
+		j := sort.Search(n, func(j int) bool { return o.before[lo+uint32(j)] > target }) - 1
+		buf = append(buf, bv.nextOffset+byte(j))
+		i = lo + uint32(j)
+	}
+}
+
+// CountPrefix returns the number of keys in the map starting with prefix
+// from the count held for the node it leads to, without enumerating them
+func (o *OrderedUint32Store) CountPrefix(prefix string) int {
+	if len(o.m.store) == 0 {
+		return 0
+	}
+	i, ok := o.m.follow(o.m.root, prefix)
+	if !ok {
+		return 0
+	}
+	return int(o.count[i])
+}
+
+// LookupString looks up the supplied string in the map
+func (o *OrderedUint32Store) LookupString(s string) (uint32, bool) { return o.m.LookupString(s) }
+
+// LookupBytes looks up the supplied byte slice in the map
+func (o *OrderedUint32Store) LookupBytes(s []byte) (uint32, bool) { return o.m.LookupBytes(s) }
+
+// Len returns the number of keys in the map
+func (o *OrderedUint32Store) Len() int {
+	if len(o.count) == 0 {
+		return 0
+	}
+	return int(o.count[0])
+}
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (o *OrderedUint32Store) Walk(fn func(string, uint32) bool) { o.m.Walk(fn) }
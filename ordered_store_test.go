@@ -0,0 +1,68 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestOrderedUint32Store(t *testing.T) {
+	m := randomSmallStrings(3000, 6)
+	ms := mapSliceN(m, len(m)/2)
+	o := faststringmap.NewOrderedUint32Store(ms)
+	checkLookuper(t, "ordered", &o, ms)
+
+	sorted := append([]string(nil), ms.in...)
+	sort.Strings(sorted)
+	for r, k := range sorted {
+		if got := o.Rank(k); got != r {
+			t.Errorf("Rank %q: got %d want %d", k, got, r)
+		}
+		if sk, v, ok := o.Select(r); !ok || sk != k || v != m[k] {
+			t.Errorf("Select %d: got %q, %d, %v want %q, %d, true", r, sk, v, ok, k, m[k])
+		}
+	}
+	for _, s := range append(ms.out, "\x00", "\xff", "a\xff\xff") {
+		if got, want := o.Rank(s), sort.SearchStrings(sorted, s); got != want {
+			t.Errorf("Rank %q: got %d want %d", s, got, want)
+		}
+	}
+	for _, r := range []int{-1, len(sorted), len(sorted) + 1} {
+		if k, _, ok := o.Select(r); ok {
+			t.Errorf("Select %d: got %q", r, k)
+		}
+	}
+
+	var zero faststringmap.OrderedUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	if zero.Rank("a") != 0 {
+		t.Error("zero Rank not 0")
+	}
+	if _, _, ok := zero.Select(0); ok {
+		t.Error("zero Select ok")
+	}
+}
+
+func TestCountPrefix(t *testing.T) {
+	m := randomSmallStrings(3000, 6)
+	o := faststringmap.NewOrderedUint32Store(faststringmap.Uint32MapSource(m))
+	for _, prefix := range []string{"", "a", "b", "ab", "!", "~~", "\xff"} {
+		want := 0
+		for k := range m {
+			if strings.HasPrefix(k, prefix) {
+				want++
+			}
+		}
+		if got := o.CountPrefix(prefix); got != want {
+			t.Errorf("CountPrefix %q: got %d want %d", prefix, got, want)
+		}
+	}
+	var zero faststringmap.OrderedUint32Store
+	if zero.CountPrefix("") != 0 {
+		t.Error("zero CountPrefix not 0")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"sort"
+	"sync"
+)
+
+type (
+	// OverlayUint32Store is a map from string to uint32 which can be
+	// changed, made of a Uint32Store holding most of the keys and a small
+	// built-in map of the changes made since it was built, which is checked
+	// first on lookup. Compact rebuilds the Uint32Store with the changes
+	// applied, and lookups and changes can continue while it does so.
+	// It is safe for concurrent use.
+	OverlayUint32Store struct {
+		compactMu sync.Mutex // held for the duration of Compact
+		mu        sync.RWMutex
+		base      Uint32Store
+		changes   map[string]overlayChange
+		n         int // number of keys
+	}
+
+	// overlayChange is a change to the value of a key in the base map
+	overlayChange struct {
+		value   uint32
+		deleted bool
+	}
+)
+
+// NewOverlayUint32Store creates an OverlayUint32Store initially holding
+// the contents of base
+func NewOverlayUint32Store(base Uint32Store) *OverlayUint32Store {
+	return &OverlayUint32Store{
+		base:    base,
+		changes: make(map[string]overlayChange),
+		n:       base.Len(),
+	}
+}
+
+// Set sets the value of key k to v
+func (o *OverlayUint32Store) Set(k string, v uint32) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.lookupString(k); !ok {
+		o.n++
+	}
+	o.changes[k] = overlayChange{value: v}
+}
+
+// Delete removes key k, if present
+func (o *OverlayUint32Store) Delete(k string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.lookupString(k); !ok {
+		return
+	}
+	o.n--
+	// keep a deletion even if k is not in base, as a Compact in progress
+	// may be adding k to base
+	o.changes[k] = overlayChange{deleted: true}
+}
+
+// LookupString looks up the supplied string in the map
+func (o *OverlayUint32Store) LookupString(s string) (uint32, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.lookupString(s)
+}
+
+func (o *OverlayUint32Store) lookupString(s string) (uint32, bool) {
+	if c, ok := o.changes[s]; ok {
+		return c.value, !c.deleted
+	}
+	return o.base.LookupString(s)
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (o *OverlayUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if c, ok := o.changes[string(s)]; ok {
+		return c.value, !c.deleted
+	}
+	return o.base.LookupBytes(s)
+}
+
+// Len returns the number of keys in the map
+func (o *OverlayUint32Store) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.n
+}
+
+// Pending returns the number of changes not yet compacted into the
+// underlying Uint32Store, so that callers can decide when to call Compact
+func (o *OverlayUint32Store) Pending() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.changes)
+}
+
+// snapshot returns the underlying map and a copy of the changes to it
+func (o *OverlayUint32Store) snapshot() (Uint32Store, map[string]overlayChange) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	changes := make(map[string]overlayChange, len(o.changes))
+	for k, c := range o.changes {
+		changes[k] = c
+	}
+	return o.base, changes
+}
+
+// Walk calls fn for each key in the map in sorted order until fn returns
+// false. It sees the map as it was when Walk was called, and fn may
+// change the map.
+func (o *OverlayUint32Store) Walk(fn func(string, uint32) bool) {
+	base, changes := o.snapshot()
+	changed := make([]string, 0, len(changes))
+	for k := range changes {
+		changed = append(changed, k)
+	}
+	sort.Strings(changed)
+
+	// merge the sorted changed keys with the keys of the underlying map
+	more := true
+	emitChanged := func(k string) bool {
+		if c := changes[k]; !c.deleted {
+			more = fn(k, c.value)
+		}
+		return more
+	}
+	base.Walk(func(k string, v uint32) bool {
+		for len(changed) > 0 && changed[0] < k {
+			if !emitChanged(changed[0]) {
+				return false
+			}
+			changed = changed[1:]
+		}
+		if len(changed) > 0 && changed[0] == k {
+			changed = changed[1:]
+			return emitChanged(k)
+		}
+		more = fn(k, v)
+		return more
+	})
+	for ; more && len(changed) > 0; changed = changed[1:] {
+		emitChanged(changed[0])
+	}
+}
+
+// Compact rebuilds the underlying Uint32Store with the changes applied.
+// The rebuild takes place without holding the lock used by lookups and
+// changes, so they are only blocked while the new map is swapped in.
+// Changes made during the rebuild are kept for the next Compact.
+func (o *OverlayUint32Store) Compact() {
+	o.compactMu.Lock()
+	defer o.compactMu.Unlock()
+
+	base, changes := o.snapshot()
+	gm := base.ToGoMap()
+	for k, c := range changes {
+		if c.deleted {
+			delete(gm, k)
+		} else {
+			gm[k] = c.value
+		}
+	}
+	rebuilt := NewUint32StoreFromMap(gm)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.base = rebuilt
+	for k, c := range changes {
+		if o.changes[k] == c {
+			delete(o.changes, k)
+		}
+	}
+}
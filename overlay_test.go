@@ -0,0 +1,103 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestOverlayUint32Store(t *testing.T) {
+	m := randomSmallStrings(2000, 6)
+	ms := mapSliceN(m, len(m)/2)
+	o := faststringmap.NewOverlayUint32Store(faststringmap.NewUint32Store(ms))
+
+	want := make(map[string]uint32, len(ms.in))
+	for _, k := range ms.in {
+		want[k] = m[k]
+	}
+	all := append(append([]string(nil), ms.in...), ms.out...)
+	for i := 0; i < 3000; i++ {
+		k := all[rand.Intn(len(all))]
+		if rand.Intn(3) == 0 {
+			o.Delete(k)
+			delete(want, k)
+		} else {
+			v := rand.Uint32()
+			o.Set(k, v)
+			want[k] = v
+		}
+		if i == 1500 {
+			checkOverlay(t, "before compact", o, want, all)
+			o.Compact()
+			if o.Pending() != 0 {
+				t.Errorf("Pending after Compact got %d want 0", o.Pending())
+			}
+		}
+	}
+	checkOverlay(t, "changed", o, want, all)
+	o.Compact()
+	checkOverlay(t, "compacted", o, want, all)
+}
+
+func checkOverlay(t *testing.T, name string, o *faststringmap.OverlayUint32Store, want map[string]uint32, all []string) {
+	t.Helper()
+	ms := mapSlice{m: want}
+	for _, k := range all {
+		if _, ok := want[k]; ok {
+			ms.in = append(ms.in, k)
+		} else {
+			ms.out = append(ms.out, k)
+		}
+	}
+	checkLookuper(t, name, o, ms)
+}
+
+func TestOverlayUint32StoreConcurrentCompact(t *testing.T) {
+	o := faststringmap.NewOverlayUint32Store(faststringmap.Uint32Store{})
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				o.Compact()
+			}
+		}
+	}()
+	for i := 0; i < 2000; i++ {
+		k := strconv.Itoa(i % 100)
+		if i%3 == 0 {
+			o.Delete(k)
+		} else {
+			o.Set(k, uint32(i))
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	want := map[string]uint32{}
+	for i := 0; i < 2000; i++ {
+		k := strconv.Itoa(i % 100)
+		if i%3 == 0 {
+			delete(want, k)
+		} else {
+			want[k] = uint32(i)
+		}
+	}
+	all := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		all = append(all, strconv.Itoa(i))
+	}
+	checkOverlay(t, "concurrent", o, want, all)
+	o.Compact()
+	checkOverlay(t, "compacted", o, want, all)
+}
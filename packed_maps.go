@@ -0,0 +1,103 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+type (
+	// PackedMaps holds many named maps from string to uint32 in a single
+	// store, avoiding the overhead of a separate store per map when there
+	// are a great many small maps. Internally each key is prefixed with
+	// the number of the map it belongs to.
+	PackedMaps struct {
+		names Uint32Store // map name to map number
+		all   Uint32Store // map number prefixed keys to values
+	}
+
+	// PackedMap is a handle for one of the maps in a PackedMaps
+	PackedMap struct {
+		all  *Uint32Store
+		root uint32 // index in store of byteValue for the map number prefix
+	}
+)
+
+// packedPrefixLen is the length of the map number prefix on keys
+const packedPrefixLen = 4
+
+// NewPackedMaps creates from the maps supplied in srcs, keyed by name
+func NewPackedMaps(srcs map[string]Uint32Source) *PackedMaps {
+	names := make([]string, 0, len(srcs))
+	for name := range srcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	nums := make(map[string]uint32, len(names))
+	var keys []string
+	for i, name := range names {
+		nums[name] = uint32(i)
+		var prefix [packedPrefixLen]byte
+		binary.BigEndian.PutUint32(prefix[:], uint32(i))
+		for _, k := range srcs[name].AppendKeys([]string(nil)) {
+			keys = append(keys, string(prefix[:])+k)
+		}
+	}
+	return &PackedMaps{
+		names: NewUint32Store(funcSource{
+			keys: names,
+			get:  func(name string) uint32 { return nums[name] },
+		}),
+		all: NewUint32Store(funcSource{
+			keys: keys,
+			get: func(k string) uint32 {
+				num := binary.BigEndian.Uint32([]byte(k[:packedPrefixLen]))
+				return srcs[names[num]].Get(k[packedPrefixLen:])
+			},
+		}),
+	}
+}
+
+// Map returns the handle for the named map, ok is false if there is no such map
+func (pm *PackedMaps) Map(name string) (m PackedMap, ok bool) {
+	num, ok := pm.names.LookupString(name)
+	if !ok {
+		return PackedMap{}, false
+	}
+	var prefix [packedPrefixLen]byte
+	binary.BigEndian.PutUint32(prefix[:], num)
+	if root, found := pm.all.follow(pm.all.root, string(prefix[:])); found {
+		return PackedMap{all: &pm.all, root: root}, true
+	}
+	// the map has no keys
+	return PackedMap{}, true
+}
+
+// LookupString looks up the supplied string in the map
+func (m PackedMap) LookupString(s string) (uint32, bool) {
+	if m.all == nil {
+		return 0, false
+	}
+	if i, ok := m.all.follow(m.root, s); ok {
+		bv := &m.all.store[i]
+		return bv.value, bv.valid
+	}
+	return 0, false
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (m PackedMap) LookupBytes(s []byte) (uint32, bool) {
+	if m.all == nil {
+		return 0, false
+	}
+	i := m.root
+	for _, b := range s {
+		var ok bool
+		if i, ok = m.all.step(i, b); !ok {
+			return 0, false
+		}
+	}
+	bv := &m.all.store[i]
+	return bv.value, bv.valid
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestPackedMaps(t *testing.T) {
+	const nMaps = 300
+	srcs := make(map[string]faststringmap.Uint32Source, nMaps)
+	slices := make(map[string]mapSlice, nMaps)
+	for i := 0; i < nMaps; i++ {
+		name := fmt.Sprintf("tenant%d", i)
+		ms := mapSliceN(randomSmallStrings(i%20+1, 4), (i%20+1)/2)
+		srcs[name], slices[name] = ms, ms
+	}
+	pm := faststringmap.NewPackedMaps(srcs)
+
+	for name, ms := range slices {
+		m, ok := pm.Map(name)
+		if !ok {
+			t.Fatalf("%s not present", name)
+		}
+		for _, k := range ms.in {
+			if v, ok := m.LookupString(k); !ok || v != ms.m[k] {
+				t.Errorf("%s %q: got %d, %v want %d, true", name, k, v, ok, ms.m[k])
+			}
+			if v, ok := m.LookupBytes([]byte(k)); !ok || v != ms.m[k] {
+				t.Errorf("%s %q: got %d, %v want %d, true", name, k, v, ok, ms.m[k])
+			}
+		}
+		for _, k := range ms.out {
+			if v, ok := m.LookupString(k); ok {
+				t.Errorf("%s %q present when not expected, got %d", name, k, v)
+			}
+			if v, ok := m.LookupBytes([]byte(k)); ok {
+				t.Errorf("%s %q present when not expected, got %d", name, k, v)
+			}
+		}
+	}
+	if _, ok := pm.Map("nobody"); ok {
+		t.Error("nobody present when not expected")
+	}
+}
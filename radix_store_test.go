@@ -0,0 +1,100 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestRadixUint32Store(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	rm := faststringmap.NewRadixUint32Store(ms)
+	checkLookuper(t, "random", &rm, ms)
+
+	ms = mapSliceN(map[string]uint32{
+		"":                 1,
+		"abcdefgh":         2,
+		"abcdefghij":       3,
+		"abcdxyz":          4,
+		"b":                5,
+		"\x00\xff":         6,
+		"\x00\x00\x00\x00": 7,
+	}, 7)
+	ms.out = []string{"a", "abc", "abcd", "abcdefg", "abcdefghi", "abcdefghijk", "abcdx", "abcdxy", "abcdxyzz", "c", "\x00", "\x00\x00"}
+	rm = faststringmap.NewRadixUint32Store(ms)
+	checkLookuper(t, "runs", &rm, ms)
+
+	// every next byte from one node
+	all := map[string]uint32{}
+	for i := 0; i < 256; i++ {
+		all["x"+string([]byte{byte(i)})+"yz"] = uint32(i)
+	}
+	ms = mapSliceN(all, len(all))
+	ms.out = []string{"x", "xa", "xay", "xayzz"}
+	rm = faststringmap.NewRadixUint32Store(ms)
+	checkLookuper(t, "wide", &rm, ms)
+
+	var zero faststringmap.RadixUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+}
+
+// uuidStrings returns n random UUID formatted strings
+func uuidStrings(n int) mapSlice {
+	r := rand.New(rand.NewSource(1))
+	m := make(map[string]uint32, n)
+	keys := make([]string, 0, n)
+	for len(keys) < n {
+		s := fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", r.Uint32(), r.Intn(1<<16), r.Intn(1<<16), r.Intn(1<<16), r.Int63n(1<<48))
+		if _, ok := m[s]; !ok {
+			m[s] = uint32(len(keys))
+			keys = append(keys, s)
+		}
+	}
+	return mapSlice{m: m, in: keys}
+}
+
+func BenchmarkRadixUint32StoreUUID(b *testing.B) {
+	m := uuidStrings(nStrsBench)
+	fm := faststringmap.NewRadixUint32Store(m)
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := fm.LookupString(m.in[si])
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
+
+func BenchmarkUint32StoreUUID(b *testing.B) {
+	m := uuidStrings(nStrsBench)
+	fm := faststringmap.NewUint32Store(m)
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := fm.LookupString(m.in[si])
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
+
+func BenchmarkGoStringToUint32UUID(b *testing.B) {
+	m := uuidStrings(nStrsBench)
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := m.m[m.in[si]]
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// CompleteRanked returns up to n keys in the map starting with prefix with
+// the highest scores given by score, highest first, with keys of the same
+// score in sorted order. Every key starting with prefix is scored, so for
+// large maps where the value is itself the score RankedCompleter is faster.
+func (m *Uint32Store) CompleteRanked(prefix string, n int, score func(string, uint32) float64) []string {
+	if n <= 0 {
+		return nil
+	}
+	var h scoredKeys // lowest score first, so the root is the one to drop
+	m.WalkPrefixBytes(prefix, make([]byte, 0, len(prefix)+256), func(key []byte, v uint32) bool {
+		s := score(string(key), v)
+		if len(h) < n {
+			heap.Push(&h, scoredKey{string(key), s})
+		} else if s > h[0].score {
+			// keys are walked in sorted order so an equal score keeps the earlier key
+			h[0] = scoredKey{string(key), s}
+			heap.Fix(&h, 0)
+		}
+		return true
+	})
+	sort.Slice(h, func(i, j int) bool { return h.Less(j, i) })
+	keys := make([]string, len(h))
+	for i := range h {
+		keys[i] = h[i].key
+	}
+	return keys
+}
+
+type (
+	scoredKey struct {
+		key   string
+		score float64
+	}
+
+	// scoredKeys is a heap with the lowest score, then the last key, first
+	scoredKeys []scoredKey
+)
+
+func (h scoredKeys) Len() int { return len(h) }
+func (h scoredKeys) Less(i, j int) bool {
+	if h[i].score != h[j].score {
+		return h[i].score < h[j].score
+	}
+	return h[i].key > h[j].key
+}
+func (h scoredKeys) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *scoredKeys) Push(x interface{}) { *h = append(*h, x.(scoredKey)) }
+func (h *scoredKeys) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// RankedCompleter returns the completions of a prefix with the highest
+// values, for example the most frequent search terms, using the highest
+// value in the sub-trie below each node, computed when it is created, to
+// visit the sub-tries in order of their best key. Only the nodes leading
+// to the keys returned, and their siblings, are visited.
+// The zero value completes nothing.
+type RankedCompleter struct {
+	m   Uint32Store
+	max []uint32 // highest value of a key in the sub-trie from each node
+}
+
+// NewRankedCompleter creates a RankedCompleter for the data supplied in src
+func NewRankedCompleter(src Uint32Source) RankedCompleter {
+	rc := RankedCompleter{m: NewUint32Store(src)}
+	rc.max = make([]uint32, len(rc.m.store))
+	rc.setMax(0)
+	return rc
+}
+
+// setMax sets max for node i and the nodes below it, using an explicit stack
+func (rc *RankedCompleter) setMax(i uint32) {
+	var frames [walkStackSize]sumFrame
+	stack := append(frames[:0], sumFrame{node: i})
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		bv := &rc.m.store[f.node]
+		if f.next < int(bv.nextLen) {
+			c := bv.nextLo + uint32(f.next)
+			f.next++
+			stack = append(stack, sumFrame{node: c})
+			continue
+		}
+		mx := f.sum // highest value in the next nodes
+		if bv.valid && bv.value > mx {
+			mx = bv.value
+		}
+		rc.max[f.node] = mx
+		if stack = stack[:len(stack)-1]; len(stack) > 0 {
+			if p := &stack[len(stack)-1]; mx > p.sum {
+				p.sum = mx
+			}
+		}
+	}
+}
+
+// Complete returns up to n keys in the map starting with prefix with the
+// highest values, highest first, with keys of the same value in sorted order
+func (rc *RankedCompleter) Complete(prefix string, n int) []string {
+	if len(rc.m.store) == 0 || n <= 0 {
+		return nil
+	}
+	i, ok := rc.m.follow(rc.m.root, prefix)
+	if !ok {
+		return nil
+	}
+	// best first search where a key is only popped once no sub-trie can
+	// have a higher value, or an equal value and an earlier key
+	q := rankQueue{{node: i, key: prefix, value: rc.max[i]}}
+	var keys []string
+	for len(q) > 0 && len(keys) < n {
+		e := heap.Pop(&q).(rankEntry)
+		if e.isKey {
+			keys = append(keys, e.key)
+			continue
+		}
+		bv := &rc.m.store[e.node]
+		if bv.valid {
+			heap.Push(&q, rankEntry{node: e.node, key: e.key, value: bv.value, isKey: true})
+		}
+		for j := uint32(0); j < uint32(bv.nextLen); j++ {
+			if next := &rc.m.store[bv.nextLo+j]; next.valid || next.nextLen > 0 {
+				heap.Push(&q, rankEntry{node: bv.nextLo + j, key: e.key + string(bv.nextOffset+byte(j)), value: rc.max[bv.nextLo+j]})
+			}
+		}
+	}
+	return keys
+}
+
+// Len returns the number of keys in the completer
+func (rc *RankedCompleter) Len() int { return rc.m.Len() }
+
+type (
+	// rankEntry is a key or a sub-trie waiting to be visited by Complete
+	rankEntry struct {
+		node  uint32
+		key   string
+		value uint32 // value of the key or highest value in the sub-trie
+		isKey bool
+	}
+
+	// rankQueue is a heap with the highest value first. For equal values
+	// the earliest key comes first, and a key before a sub-trie starting
+	// with it, as all the keys in the sub-trie come after it.
+	rankQueue []rankEntry
+)
+
+func (q rankQueue) Len() int { return len(q) }
+func (q rankQueue) Less(i, j int) bool {
+	if q[i].value != q[j].value {
+		return q[i].value > q[j].value
+	}
+	if q[i].key != q[j].key {
+		return q[i].key < q[j].key
+	}
+	return q[i].isKey && !q[j].isKey
+}
+func (q rankQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *rankQueue) Push(x interface{}) { *q = append(*q, x.(rankEntry)) }
+func (q *rankQueue) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestCompleteRanked(t *testing.T) {
+	freq := map[string]uint32{
+		"go": 50, "golang": 90, "google": 100, "gopher": 90, "gold": 10, "good": 90, "java": 200,
+	}
+	fm := faststringmap.NewUint32StoreFromMap(freq)
+	rc := faststringmap.NewRankedCompleter(faststringmap.Uint32MapSource(freq))
+	byValue := func(_ string, v uint32) float64 { return float64(v) }
+	for _, tc := range []struct {
+		prefix string
+		n      int
+		want   []string
+	}{
+		{"go", 3, []string{"google", "golang", "good"}},
+		{"go", 5, []string{"google", "golang", "good", "gopher", "go"}},
+		{"gol", 5, []string{"golang", "gold"}},
+		{"", 1, []string{"java"}},
+		{"x", 3, nil},
+		{"go", 0, nil},
+	} {
+		if got := fm.CompleteRanked(tc.prefix, tc.n, byValue); !equalStrings(got, tc.want) {
+			t.Errorf("CompleteRanked %q %d: got %q want %q", tc.prefix, tc.n, got, tc.want)
+		}
+		if got := rc.Complete(tc.prefix, tc.n); !equalStrings(got, tc.want) {
+			t.Errorf("RankedCompleter %q %d: got %q want %q", tc.prefix, tc.n, got, tc.want)
+		}
+	}
+	byLength := func(k string, _ uint32) float64 { return float64(len(k)) }
+	if got, want := fm.CompleteRanked("go", 2, byLength), []string{"golang", "google"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("by length: got %q want %q", got, want)
+	}
+
+	// check against sorting all the keys with the prefix
+	r := rand.New(rand.NewSource(1))
+	m := randomSmallStrings(3000, 6)
+	for k := range m {
+		m[k] = uint32(r.Intn(50))
+	}
+	fm = faststringmap.NewUint32StoreFromMap(m)
+	rc = faststringmap.NewRankedCompleter(faststringmap.Uint32MapSource(m))
+	for _, prefix := range []string{"", "a", "b", "!", "~"} {
+		var want []string
+		for k := range m {
+			if strings.HasPrefix(k, prefix) {
+				want = append(want, k)
+			}
+		}
+		sort.Slice(want, func(i, j int) bool {
+			if m[want[i]] != m[want[j]] {
+				return m[want[i]] > m[want[j]]
+			}
+			return want[i] < want[j]
+		})
+		if len(want) > 20 {
+			want = want[:20]
+		}
+		if got := fm.CompleteRanked(prefix, 20, byValue); !equalStrings(got, want) {
+			t.Errorf("CompleteRanked %q: got %q want %q", prefix, got, want)
+		}
+		if got := rc.Complete(prefix, 20); !equalStrings(got, want) {
+			t.Errorf("RankedCompleter %q: got %q want %q", prefix, got, want)
+		}
+	}
+
+	var zero faststringmap.RankedCompleter
+	if got := zero.Complete("", 3); got != nil || zero.Len() != 0 {
+		t.Errorf("zero got %q", got)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	// readerAtPageNodes is the number of byteValues read at a time
+	readerAtPageNodes = 256
+	// DefaultReaderAtCachePages is the number of pages cached by a
+	// ReaderAtStore opened by OpenReaderAt. Each page is 256 nodes, read
+	// as 3KiB, so the default cache holds 768KiB of the persisted map.
+	DefaultReaderAtCachePages = 256
+)
+
+type (
+	// ReaderAtStore looks up keys in a map persisted by Uint32Store.WriteTo,
+	// reading the parts of it needed on demand rather than loading it all.
+	// Recently used parts are cached. It is safe for concurrent use.
+	ReaderAtStore struct {
+		ra         io.ReaderAt
+		nodes      uint32
+		cachePages int
+
+		mu      sync.Mutex
+		pages   map[uint32]*list.Element // page number to element in lru
+		lru     *list.List               // of *readerAtPage, most recently used first
+		loading map[uint32]*readerAtLoad // page number to read in progress
+	}
+
+	readerAtPage struct {
+		num   uint32
+		nodes []byteValue
+	}
+
+	// readerAtLoad is a read of a page which other lookups of the page wait for
+	readerAtLoad struct {
+		done chan struct{} // closed when the read is complete
+		page *readerAtPage
+		err  error
+	}
+)
+
+// OpenReaderAt opens the map of size bytes persisted by Uint32Store.WriteTo
+// which can be read from ra, caching DefaultReaderAtCachePages pages. Only
+// the header is checked, use Verify to check the whole map.
+func OpenReaderAt(ra io.ReaderAt, size int64) (*ReaderAtStore, error) {
+	return OpenReaderAtCache(ra, size, DefaultReaderAtCachePages)
+}
+
+// OpenReaderAtCache is like OpenReaderAt but caches up to cachePages pages
+// of 256 nodes, at least one
+func OpenReaderAtCache(ra io.ReaderAt, size int64, cachePages int) (*ReaderAtStore, error) {
+	if cachePages < 1 {
+		cachePages = 1
+	}
+	hdr := make([]byte, persistHeaderSize)
+	if err := readFullAt(ra, hdr, 0); err != nil {
+		return nil, err
+	}
+	h, err := parseHeader(hdr)
+	if err != nil {
+		return nil, err
+	}
+	if want := persistHeaderSize + int64(h.nodes)*persistNodeSize; size != want {
+		return nil, fmt.Errorf("%w: size %d want %d for %d nodes", ErrInvalidData, size, want, h.nodes)
+	}
+	return &ReaderAtStore{
+		ra:         ra,
+		nodes:      h.nodes,
+		cachePages: cachePages,
+		pages:      make(map[uint32]*list.Element),
+		lru:        list.New(),
+		loading:    make(map[uint32]*readerAtLoad),
+	}, nil
+}
+
+// LookupString looks up the supplied string in the map.
+// An error is returned if reading fails or the data is invalid.
+func (m *ReaderAtStore) LookupString(s string) (uint32, bool, error) {
+	bv, err := m.node(0)
+	for i, n := 0, len(s); i < n && err == nil; i++ {
+		b := s[i]
+		if b < bv.nextOffset || uint16(b-bv.nextOffset) >= bv.nextLen {
+			return 0, false, nil
+		}
+		bv, err = m.node(bv.nextLo + uint32(b-bv.nextOffset))
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return bv.value, bv.valid, nil
+}
+
+// LookupBytes looks up the supplied byte slice in the map.
+// An error is returned if reading fails or the data is invalid.
+func (m *ReaderAtStore) LookupBytes(s []byte) (uint32, bool, error) {
+	bv, err := m.node(0)
+	for i, n := 0, len(s); i < n && err == nil; i++ {
+		b := s[i]
+		if b < bv.nextOffset || uint16(b-bv.nextOffset) >= bv.nextLen {
+			return 0, false, nil
+		}
+		bv, err = m.node(bv.nextLo + uint32(b-bv.nextOffset))
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return bv.value, bv.valid, nil
+}
+
+// node returns byteValue i, reading its page if it is not cached. The
+// lock is not held while reading, so a slow read only holds up lookups
+// which need the same page, which wait for it rather than reading it again.
+func (m *ReaderAtStore) node(i uint32) (byteValue, error) {
+	if i >= m.nodes {
+		return byteValue{}, fmt.Errorf("%w: node %d out of range", ErrInvalidData, i)
+	}
+	num := i / readerAtPageNodes
+	m.mu.Lock()
+	if e, ok := m.pages[num]; ok {
+		m.lru.MoveToFront(e)
+		bv := e.Value.(*readerAtPage).nodes[i%readerAtPageNodes]
+		m.mu.Unlock()
+		return bv, nil
+	}
+	if ld, ok := m.loading[num]; ok {
+		m.mu.Unlock()
+		<-ld.done
+		if ld.err != nil {
+			return byteValue{}, ld.err
+		}
+		return ld.page.nodes[i%readerAtPageNodes], nil
+	}
+	ld := &readerAtLoad{done: make(chan struct{})}
+	m.loading[num] = ld
+	m.mu.Unlock()
+
+	ld.page, ld.err = m.readPage(num)
+
+	m.mu.Lock()
+	delete(m.loading, num)
+	if ld.err == nil {
+		m.pages[num] = m.lru.PushFront(ld.page)
+		if m.lru.Len() > m.cachePages {
+			old := m.lru.Remove(m.lru.Back()).(*readerAtPage)
+			delete(m.pages, old.num)
+		}
+	}
+	m.mu.Unlock()
+	close(ld.done)
+	if ld.err != nil {
+		return byteValue{}, ld.err
+	}
+	return ld.page.nodes[i%readerAtPageNodes], nil
+}
+
+// readPage reads page num of the nodes
+func (m *ReaderAtStore) readPage(num uint32) (*readerAtPage, error) {
+	lo := num * readerAtPageNodes
+	n := m.nodes - lo
+	if n > readerAtPageNodes {
+		n = readerAtPageNodes
+	}
+	buf := make([]byte, n*persistNodeSize)
+	if err := readFullAt(m.ra, buf, persistHeaderSize+int64(lo)*persistNodeSize); err != nil {
+		return nil, err
+	}
+	p := &readerAtPage{num: num, nodes: make([]byteValue, n)}
+	for j := range p.nodes {
+		p.nodes[j] = getNode(buf[j*persistNodeSize:])
+	}
+	return p, nil
+}
+
+// readFullAt reads len(b) bytes at off from ra, which may report io.EOF
+// along with all the bytes when reading up to the end
+func readFullAt(ra io.ReaderAt, b []byte, off int64) error {
+	n, err := ra.ReadAt(b, off)
+	switch {
+	case n == len(b):
+		return nil
+	case err == nil || err == io.EOF:
+		return fmt.Errorf("%w: truncated", ErrInvalidData)
+	}
+	return err
+}
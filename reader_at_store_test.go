@@ -0,0 +1,126 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestOpenReaderAt(t *testing.T) {
+	m := randomSmallStrings(20000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	var buf bytes.Buffer
+	if _, err := fm.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := fm.MarshalBinary()
+	if !bytes.Equal(b, buf.Bytes()) {
+		t.Error("MarshalBinary and WriteTo differ")
+	}
+
+	ra, err := faststringmap.OpenReaderAt(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range ms.in {
+		if v, ok, err := ra.LookupString(k); err != nil || !ok || v != m[k] {
+			t.Errorf("%q: got %d, %v, %v want %d, true, nil", k, v, ok, err, m[k])
+		}
+		if v, ok, err := ra.LookupBytes([]byte(k)); err != nil || !ok || v != m[k] {
+			t.Errorf("%q: got %d, %v, %v want %d, true, nil", k, v, ok, err, m[k])
+		}
+	}
+	for _, k := range ms.out {
+		if v, ok, err := ra.LookupString(k); err != nil || ok {
+			t.Errorf("%q: got %d, %v, %v want not present", k, v, ok, err)
+		}
+	}
+
+	for _, bad := range [][]byte{b[:len(b)-1], append([]byte("XXXX"), b[4:]...), b[:8]} {
+		if _, err := faststringmap.OpenReaderAt(bytes.NewReader(bad), int64(len(bad))); !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("got error %v want %v", err, faststringmap.ErrInvalidData)
+		}
+	}
+}
+
+// blockingReaderAt is an io.ReaderAt whose reads wait for unblock once
+// blocking is set, sending on started as they start waiting
+type blockingReaderAt struct {
+	r        io.ReaderAt
+	blocking int32
+	started  chan struct{}
+	unblock  chan struct{}
+}
+
+func (b *blockingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if atomic.LoadInt32(&b.blocking) != 0 {
+		b.started <- struct{}{}
+		<-b.unblock
+	}
+	return b.r.ReadAt(p, off)
+}
+
+func TestReaderAtStoreSlowRead(t *testing.T) {
+	m := randomSmallStrings(20000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	b, _ := fm.MarshalBinary()
+	bra := &blockingReaderAt{r: bytes.NewReader(b), started: make(chan struct{}, 2), unblock: make(chan struct{})}
+	ra, err := faststringmap.OpenReaderAtCache(bra, int64(len(b)), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the empty string only needs the root, which is then cached
+	wantV, wantOK := fm.LookupString("")
+	if v, ok, err := ra.LookupString(""); err != nil || v != wantV || ok != wantOK {
+		t.Fatalf("empty string: got %d, %v, %v", v, ok, err)
+	}
+
+	// the largest key has the last nodes, so is not in the cached page
+	sort.Strings(ms.in)
+	k := ms.in[len(ms.in)-1]
+	atomic.StoreInt32(&bra.blocking, 1)
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			v, ok, err := ra.LookupString(k)
+			if err == nil && (!ok || v != m[k]) {
+				err = errors.New("wrong value")
+			}
+			results <- err
+		}()
+	}
+	<-bra.started
+
+	// a lookup of a cached page does not wait for the slow read
+	done := make(chan struct{})
+	go func() {
+		ra.LookupString("")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lookup of cached page waited for slow read")
+	}
+
+	atomic.StoreInt32(&bra.blocking, 0)
+	close(bra.unblock)
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("%q: %v", k, err)
+		}
+	}
+	if n := len(bra.started); n != 0 {
+		t.Errorf("%d more reads of the page started while it was being read", n)
+	}
+}
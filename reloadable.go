@@ -0,0 +1,69 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ReloadableUint32Store holds a Uint32Store which can be replaced while
+// lookups are taking place, so that a service can build a new map from
+// fresh data in the background and switch to it without locking. Each
+// lookup sees either the old or the new map. It is safe for concurrent
+// use, and the zero value holds an empty map.
+type ReloadableUint32Store struct {
+	v  atomic.Value // *Uint32Store
+	mu sync.Mutex   // held while replacing the map, so Swap is atomic
+}
+
+// NewReloadableUint32Store creates a ReloadableUint32Store holding m
+func NewReloadableUint32Store(m Uint32Store) *ReloadableUint32Store {
+	r := &ReloadableUint32Store{}
+	r.v.Store(&m)
+	return r
+}
+
+// Load returns the map currently held, which stays valid after a Swap
+func (r *ReloadableUint32Store) Load() *Uint32Store {
+	if m, ok := r.v.Load().(*Uint32Store); ok {
+		return m
+	}
+	return &Uint32Store{}
+}
+
+// Swap replaces the map held with m and returns the previous map
+func (r *ReloadableUint32Store) Swap(m Uint32Store) *Uint32Store {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.Load()
+	r.v.Store(&m)
+	return old
+}
+
+// Rebuild builds a new map from the data supplied in src and replaces
+// the map held with it. Lookups continue to use the previous map until
+// the build is complete.
+func (r *ReloadableUint32Store) Rebuild(src Uint32Source) {
+	m := NewUint32Store(src)
+	r.mu.Lock()
+	r.v.Store(&m)
+	r.mu.Unlock()
+}
+
+// LookupString looks up the supplied string in the map
+func (r *ReloadableUint32Store) LookupString(s string) (uint32, bool) {
+	return r.Load().LookupString(s)
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (r *ReloadableUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	return r.Load().LookupBytes(s)
+}
+
+// Len returns the number of keys in the map
+func (r *ReloadableUint32Store) Len() int { return r.Load().Len() }
+
+// Walk calls fn for each key in the map in sorted order until fn returns
+// false. It walks the map held when Walk was called.
+func (r *ReloadableUint32Store) Walk(fn func(string, uint32) bool) { r.Load().Walk(fn) }
@@ -0,0 +1,87 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestReloadableUint32Store(t *testing.T) {
+	var zero faststringmap.ReloadableUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+
+	m1 := map[string]uint32{"a": 1, "b": 2}
+	m2 := map[string]uint32{"b": 20, "c": 30}
+	r := faststringmap.NewReloadableUint32Store(faststringmap.NewUint32StoreFromMap(m1))
+	checkLookuper(t, "first", r, mapSlice{m: m1, in: []string{"a", "b"}, out: []string{"c"}})
+
+	old := r.Swap(faststringmap.NewUint32StoreFromMap(m2))
+	checkLookuper(t, "swapped", r, mapSlice{m: m2, in: []string{"b", "c"}, out: []string{"a"}})
+	checkLookuper(t, "old", old, mapSlice{m: m1, in: []string{"a", "b"}, out: []string{"c"}})
+
+	r.Rebuild(faststringmap.Uint32MapSource(m1))
+	checkLookuper(t, "rebuilt", r, mapSlice{m: m1, in: []string{"a", "b"}, out: []string{"c"}})
+
+	// readers see one map or the other while it is being replaced
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				v, ok := r.LookupString("b")
+				if !ok || (v != 2 && v != 20) {
+					t.Errorf("b: got %d, %v", v, ok)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			r.Rebuild(faststringmap.Uint32MapSource(m2))
+		} else {
+			r.Swap(faststringmap.NewUint32StoreFromMap(m1))
+		}
+	}
+	wg.Wait()
+}
+
+func TestReloadableUint32StoreConcurrentSwap(t *testing.T) {
+	// map i has i keys, so each map swapped in can be identified by Len
+	const n = 64
+	maps := make([]faststringmap.Uint32Store, n+1)
+	for i := range maps {
+		m := make(map[string]uint32, i)
+		for len(m) < i {
+			m[string(rune('a'+len(m)))] = 0
+		}
+		maps[i] = faststringmap.NewUint32StoreFromMap(m)
+	}
+	r := faststringmap.NewReloadableUint32Store(maps[0])
+	prev := make([]int, n+1)
+	var wg sync.WaitGroup
+	for i := 1; i <= n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			prev[i] = r.Swap(maps[i]).Len()
+		}(i)
+	}
+	wg.Wait()
+
+	// every map is returned once as a previous map, except the last held
+	seen := make([]int, n+1)
+	seen[r.Len()]++
+	for _, p := range prev[1:] {
+		seen[p]++
+	}
+	for i, c := range seen {
+		if c != 1 {
+			t.Errorf("map with %d keys seen %d times want once", i, c)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+type (
+	// SegmentMode chooses how Segment splits text into keys
+	SegmentMode int
+
+	// Segment is a part of the text split by Segment
+	Segment struct {
+		Start, End int    // text[Start:End] is the segment
+		Value      uint32 // value for the key if Known
+		Known      bool   // whether the segment is a key in the map
+	}
+)
+
+const (
+	// SegmentGreedy takes the longest key at each position
+	SegmentGreedy SegmentMode = iota
+	// SegmentOptimal covers as much of the text with keys as possible,
+	// then uses as few segments as possible
+	SegmentOptimal
+)
+
+// Segment splits text into keys of the map, for example words of a
+// dictionary for text without spaces or parts of log tokens. Runs of bytes
+// which are not covered by a key are returned as segments which are not
+// Known. The empty string is never used as a key.
+func (m *Uint32Store) Segment(text []byte, mode SegmentMode) []Segment {
+	if mode == SegmentOptimal {
+		return m.segmentOptimal(text)
+	}
+	var segs []Segment
+	for i := 0; i < len(text); {
+		if v, n, ok := m.LookupLongestPrefixBytes(text[i:]); ok && n > 0 {
+			segs = append(segs, Segment{Start: i, End: i + n, Value: v, Known: true})
+			i += n
+			continue
+		}
+		segs = appendUnknown(segs, i)
+		i++
+	}
+	return segs
+}
+
+// segmentCost is the cost of segmenting the text from a position
+type segmentCost struct {
+	unknown int // bytes not covered by keys
+	segs    int // number of segments, counting each unknown byte as one
+}
+
+func (c segmentCost) less(d segmentCost) bool {
+	if c.unknown != d.unknown {
+		return c.unknown < d.unknown
+	}
+	return c.segs < d.segs
+}
+
+// segmentOptimal finds the segmentation of text of lowest cost working
+// back from the end, then follows it forwards
+func (m *Uint32Store) segmentOptimal(text []byte) []Segment {
+	cost := make([]segmentCost, len(text)+1)
+	next := make([]Match, len(text)) // best first segment from each position
+	for i := len(text) - 1; i >= 0; i-- {
+		cost[i] = segmentCost{cost[i+1].unknown + 1, cost[i+1].segs + 1}
+		next[i] = Match{Start: i, End: i + 1}
+		for _, mt := range m.LookupAllPrefixesBytes(text[i:]) {
+			if mt.End == 0 {
+				continue
+			}
+			c := cost[i+mt.End]
+			if c.segs++; c.less(cost[i]) {
+				cost[i] = c
+				next[i] = Match{Start: i, End: i + mt.End, Value: mt.Value}
+			}
+		}
+	}
+	var segs []Segment
+	for i := 0; i < len(text); i = next[i].End {
+		if nx := next[i]; nx.End-nx.Start == 1 && cost[i].unknown == cost[i+1].unknown+1 {
+			segs = appendUnknown(segs, i)
+		} else {
+			segs = append(segs, Segment{Start: nx.Start, End: nx.End, Value: nx.Value, Known: true})
+		}
+	}
+	return segs
+}
+
+// appendUnknown adds the byte at i to segs as unknown, extending the last
+// segment if it is an unknown one ending at i
+func appendUnknown(segs []Segment, i int) []Segment {
+	if n := len(segs); n > 0 && !segs[n-1].Known && segs[n-1].End == i {
+		segs[n-1].End++
+		return segs
+	}
+	return append(segs, Segment{Start: i, End: i + 1})
+}
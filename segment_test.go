@@ -0,0 +1,53 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestSegment(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"": 0, "ab": 1, "abc": 2, "cd": 3, "e": 4, "東京": 5, "都": 6,
+	})
+	type seg = faststringmap.Segment
+	for _, tc := range []struct {
+		text            string
+		greedy, optimal []seg
+	}{
+		{
+			"abcd",
+			[]seg{{0, 3, 2, true}, {3, 4, 0, false}},
+			[]seg{{0, 2, 1, true}, {2, 4, 3, true}},
+		},
+		{
+			"xxabcdey",
+			[]seg{{0, 2, 0, false}, {2, 5, 2, true}, {5, 6, 0, false}, {6, 7, 4, true}, {7, 8, 0, false}},
+			[]seg{{0, 2, 0, false}, {2, 4, 1, true}, {4, 6, 3, true}, {6, 7, 4, true}, {7, 8, 0, false}},
+		},
+		{
+			"東京都庁",
+			[]seg{{0, 6, 5, true}, {6, 9, 6, true}, {9, 12, 0, false}},
+			[]seg{{0, 6, 5, true}, {6, 9, 6, true}, {9, 12, 0, false}},
+		},
+		{"", nil, nil},
+	} {
+		if got := fm.Segment([]byte(tc.text), faststringmap.SegmentGreedy); !reflect.DeepEqual(got, tc.greedy) {
+			t.Errorf("greedy %q: got %v want %v", tc.text, got, tc.greedy)
+		}
+		if got := fm.Segment([]byte(tc.text), faststringmap.SegmentOptimal); !reflect.DeepEqual(got, tc.optimal) {
+			t.Errorf("optimal %q: got %v want %v", tc.text, got, tc.optimal)
+		}
+	}
+
+	var zero faststringmap.Uint32Store
+	want := []seg{{0, 3, 0, false}}
+	for _, mode := range []faststringmap.SegmentMode{faststringmap.SegmentGreedy, faststringmap.SegmentOptimal} {
+		if got := zero.Segment([]byte("abc"), mode); !reflect.DeepEqual(got, want) {
+			t.Errorf("zero mode %d: got %v want %v", mode, got, want)
+		}
+	}
+}
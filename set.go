@@ -0,0 +1,88 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+type (
+	// Set is a fast read only set of strings. It is a Uint32Store without
+	// the values, so each node takes 8 bytes rather than 12.
+	// The zero value is an empty set.
+	Set struct {
+		nodes []setNode
+		n     int // number of strings
+	}
+
+	// setNode is a byteValue without the value
+	setNode struct {
+		nextLo     uint32 // index in nodes of next setNodes
+		nextLen    uint16 // number of setNodes used for next possible bytes
+		nextOffset byte   // offset from zero byte value of first element of range of setNodes
+		valid      bool   // is the byte sequence with no more bytes in the set?
+	}
+)
+
+// NewSetFromKeys creates a set of the strings in keys, which may contain
+// duplicates and be in any order. keys is not modified.
+func NewSetFromKeys(keys []string) Set {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	unique := sorted[:0]
+	for i, k := range sorted {
+		if i == 0 || k != sorted[i-1] {
+			unique = append(unique, k)
+		}
+	}
+	if len(unique) == 0 {
+		return Set{}
+	}
+	store := uint32Build(unique, func(int) uint32 { return 0 })
+	nodes := make([]setNode, len(store))
+	for i := range store {
+		bv := &store[i]
+		nodes[i] = setNode{nextLo: bv.nextLo, nextLen: bv.nextLen, nextOffset: bv.nextOffset, valid: bv.valid}
+	}
+	return Set{nodes: nodes, n: len(unique)}
+}
+
+// Contains reports whether the supplied string is in the set
+func (s *Set) Contains(k string) bool {
+	if len(s.nodes) == 0 {
+		return false
+	}
+	nd := &s.nodes[0]
+	for i, n := 0, len(k); i < n; i++ {
+		b := k[i]
+		if b < nd.nextOffset {
+			return false
+		}
+		ni := b - nd.nextOffset
+		if uint16(ni) >= nd.nextLen {
+			return false
+		}
+		nd = &s.nodes[nd.nextLo+uint32(ni)]
+	}
+	return nd.valid
+}
+
+// ContainsBytes reports whether the supplied byte slice is in the set
+func (s *Set) ContainsBytes(k []byte) bool {
+	if len(s.nodes) == 0 {
+		return false
+	}
+	nd := &s.nodes[0]
+	for _, b := range k {
+		if b < nd.nextOffset {
+			return false
+		}
+		ni := b - nd.nextOffset
+		if uint16(ni) >= nd.nextLen {
+			return false
+		}
+		nd = &s.nodes[nd.nextLo+uint32(ni)]
+	}
+	return nd.valid
+}
+
+// Len returns the number of strings in the set
+func (s *Set) Len() int { return s.n }
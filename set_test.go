@@ -0,0 +1,41 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestSet(t *testing.T) {
+	m := randomSmallStrings(1000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	keys := append(append([]string(nil), ms.in...), ms.in[:100]...)
+	s := faststringmap.NewSetFromKeys(keys)
+
+	if s.Len() != len(ms.in) {
+		t.Errorf("Len got %d want %d", s.Len(), len(ms.in))
+	}
+	for _, k := range ms.in {
+		if !s.Contains(k) || !s.ContainsBytes([]byte(k)) {
+			t.Errorf("%q not present", k)
+		}
+	}
+	for _, k := range ms.out {
+		if s.Contains(k) || s.ContainsBytes([]byte(k)) {
+			t.Errorf("%q present when not expected", k)
+		}
+	}
+
+	for _, empty := range []faststringmap.Set{faststringmap.NewSetFromKeys(nil), {}} {
+		if empty.Contains("") || empty.ContainsBytes(nil) || empty.Len() != 0 {
+			t.Error("empty set not empty")
+		}
+	}
+
+	s = faststringmap.NewSetFromKeys([]string{""})
+	if !s.Contains("") || s.Contains("a") || s.Len() != 1 {
+		t.Error("set of empty string wrong")
+	}
+}
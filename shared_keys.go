@@ -0,0 +1,110 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+type (
+	// SharedKeys is a vocabulary of keys common to many maps. The trie for
+	// the vocabulary is stored once, and maps built with it only store the
+	// vocabulary keys they hold, as a bit for each term number, with their
+	// values, plus a trie for any keys outside it. So each map takes four
+	// bytes for each of its own keys and under two bits for each key of the
+	// vocabulary, rather than a trie for all its keys. Looking up a term
+	// number found in the vocabulary trie takes constant time.
+	SharedKeys struct {
+		index Uint32Store // key to term number
+		keys  []string    // key for each term number, in sorted order
+	}
+
+	// SharedUint32Store is a fast read only map from string to uint32
+	// built against a SharedKeys vocabulary. The zero value is an empty map.
+	SharedUint32Store struct {
+		keys   *SharedKeys
+		terms  bitVector   // bit for each term number, set for the vocabulary keys in the map
+		values []uint32    // value for each set bit of terms
+		extra  Uint32Store // keys not in the vocabulary
+	}
+
+	// termValue is a term number and its value in a SharedUint32Store
+	termValue struct{ term, value uint32 }
+)
+
+// NewSharedKeys creates a vocabulary from keys, ignoring duplicates
+func NewSharedKeys(keys []string) *SharedKeys {
+	unique := append([]string(nil), keys...)
+	sort.Strings(unique)
+	n := 0
+	for i, k := range unique {
+		if i == 0 || k != unique[n-1] {
+			unique[n] = k
+			n++
+		}
+	}
+	unique = unique[:n:n]
+	// term numbers are in the same order as the keys
+	return &SharedKeys{
+		index: newUint32StoreSorted(unique, func(i int) uint32 { return uint32(i) }),
+		keys:  unique,
+	}
+}
+
+// NewUint32Store creates a map from the data supplied in src using the vocabulary
+func (sk *SharedKeys) NewUint32Store(src Uint32Source) SharedUint32Store {
+	var tvs []termValue
+	var extra []string
+	for _, k := range src.AppendKeys([]string(nil)) {
+		if t, ok := sk.index.LookupString(k); ok {
+			tvs = append(tvs, termValue{t, src.Get(k)})
+		} else {
+			extra = append(extra, k)
+		}
+	}
+	sort.Slice(tvs, func(i, j int) bool { return tvs[i].term < tvs[j].term })
+	m := SharedUint32Store{
+		keys:   sk,
+		values: make([]uint32, len(tvs)),
+		extra:  NewUint32Store(funcSource{keys: extra, get: src.Get}),
+	}
+	j := 0
+	for t := range sk.keys {
+		in := j < len(tvs) && tvs[j].term == uint32(t)
+		if in {
+			m.values[j] = tvs[j].value
+			j++
+		}
+		m.terms.push(in)
+	}
+	m.terms.finish()
+	return m
+}
+
+// LookupString looks up the supplied string in the map
+func (m *SharedUint32Store) LookupString(s string) (uint32, bool) {
+	if m.keys == nil {
+		return 0, false
+	}
+	if t, ok := m.keys.index.LookupString(s); ok {
+		return m.term(t)
+	}
+	return m.extra.LookupString(s)
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (m *SharedUint32Store) LookupBytes(s []byte) (uint32, bool) {
+	if m.keys == nil {
+		return 0, false
+	}
+	if t, ok := m.keys.index.LookupBytes(s); ok {
+		return m.term(t)
+	}
+	return m.extra.LookupBytes(s)
+}
+
+// term returns the value for term number t if it is in the map
+func (m *SharedUint32Store) term(t uint32) (uint32, bool) {
+	if !m.terms.get(int(t)) {
+		return 0, false
+	}
+	return m.values[m.terms.rank1(int(t))], true
+}
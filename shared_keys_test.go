@@ -0,0 +1,72 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestSharedKeys(t *testing.T) {
+	vocab := randomSmallStrings(1000, 8)
+	var keys []string
+	for k := range vocab {
+		keys = append(keys, k)
+	}
+	sk := faststringmap.NewSharedKeys(append(keys, keys[:10]...))
+
+	// tenant maps each use part of the vocabulary plus some keys of their own
+	for tenant := 0; tenant < 3; tenant++ {
+		m := make(map[string]uint32)
+		for i, k := range keys {
+			if i%3 == tenant {
+				m[k] = uint32(i * (tenant + 1))
+			}
+		}
+		for k, v := range randomSmallStrings(50, 12) {
+			if _, ok := vocab[k]; !ok {
+				m[k] = v
+			}
+		}
+		ms := mapSliceN(m, len(m)*2/3)
+		fm := sk.NewUint32Store(ms)
+		checkLookuper(t, "tenant "+strconv.Itoa(tenant), &fm, ms)
+		for _, k := range ms.in {
+			if v, ok := fm.LookupString(k); !ok || v != m[k] {
+				t.Errorf("tenant %d %q: got %d, %v want %d, true", tenant, k, v, ok, m[k])
+			}
+			if v, ok := fm.LookupBytes([]byte(k)); !ok || v != m[k] {
+				t.Errorf("tenant %d %q: got %d, %v want %d, true", tenant, k, v, ok, m[k])
+			}
+		}
+		for _, k := range append(ms.out, keys[(tenant+1)%3]) {
+			if v, ok := fm.LookupString(k); ok {
+				t.Errorf("tenant %d %q present when not expected, got %d", tenant, k, v)
+			}
+		}
+	}
+}
+
+func BenchmarkSharedUint32Store(b *testing.B) {
+	m := uuidStrings(nStrsBench)
+	sk := faststringmap.NewSharedKeys(m.in)
+	shared := sk.NewUint32Store(m)
+	plain := faststringmap.NewUint32Store(m)
+	for _, c := range []struct {
+		name string
+		fm   faststringmap.Lookuper
+	}{{"shared", &shared}, {"plain", &plain}} {
+		b.Run(c.name, func(b *testing.B) {
+			for bi := 0; bi < b.N; bi++ {
+				for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+					v, ok := c.fm.LookupString(m.in[si])
+					if !ok || v != si {
+						b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+// SkipBytesStore is a fast read only map from string to uint32 which
+// ignores a set of bytes, for example optional punctuation, in both the
+// keys it is built from and the strings looked up.
+// The zero value is an empty map which skips no bytes.
+type SkipBytesStore struct {
+	m    Uint32Store // keys with skipped bytes removed
+	skip [256]bool
+}
+
+// NewSkipBytesStore creates from the data supplied in src ignoring the bytes
+// in skip. If several keys are the same once the bytes are removed then the
+// value is that of the first of them in sorted order.
+func NewSkipBytesStore(src Uint32Source, skip string) SkipBytesStore {
+	var m SkipBytesStore
+	for i := 0; i < len(skip); i++ {
+		m.skip[skip[i]] = true
+	}
+	keys := src.AppendKeys([]string(nil))
+	sort.Strings(keys)
+	orig := make(map[string]string, len(keys)) // stripped key to first original key
+	stripped := keys[:0]
+	for _, k := range keys {
+		s := m.strip(k)
+		if _, ok := orig[s]; !ok {
+			orig[s] = k
+			stripped = append(stripped, s)
+		}
+	}
+	m.m = NewUint32Store(funcSource{
+		keys: stripped,
+		get:  func(s string) uint32 { return src.Get(orig[s]) },
+	})
+	return m
+}
+
+// strip returns s without the skipped bytes
+func (m *SkipBytesStore) strip(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if !m.skip[s[i]] {
+			b = append(b, s[i])
+		}
+	}
+	return string(b)
+}
+
+// LookupString looks up the supplied string in the map ignoring the skipped bytes
+func (m *SkipBytesStore) LookupString(s string) (uint32, bool) {
+	if len(m.m.store) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	for j, n := 0, len(s); j < n; j++ {
+		if b := s[j]; !m.skip[b] {
+			var ok bool
+			if i, ok = m.m.step(i, b); !ok {
+				return 0, false
+			}
+		}
+	}
+	bv := &m.m.store[i]
+	return bv.value, bv.valid
+}
+
+// LookupBytes looks up the supplied byte slice in the map ignoring the skipped bytes
+func (m *SkipBytesStore) LookupBytes(s []byte) (uint32, bool) {
+	if len(m.m.store) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	for _, b := range s {
+		if !m.skip[b] {
+			var ok bool
+			if i, ok = m.m.step(i, b); !ok {
+				return 0, false
+			}
+		}
+	}
+	bv := &m.m.store[i]
+	return bv.value, bv.valid
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestSkipBytesStore(t *testing.T) {
+	m := map[string]uint32{
+		"AB-123":  1,
+		"AB 123":  2, // same as AB-123 once stripped
+		"CD.4-5":  3,
+		"EF":      4,
+		"-":       5, // same as empty string once stripped
+		"GH-1234": 6,
+	}
+	fm := faststringmap.NewSkipBytesStore(mapSliceN(m, len(m)), "- .")
+	for _, tc := range []struct {
+		key   string
+		value uint32
+		ok    bool
+	}{
+		{"AB123", 2, true},
+		{"AB-123", 2, true},
+		{"A-B-1-2-3", 2, true},
+		{"CD45", 3, true},
+		{"C.D 4.5", 3, true},
+		{"EF", 4, true},
+		{"", 5, true},
+		{"--", 5, true},
+		{"GH 1234", 6, true},
+		{"GH123", 0, false},
+		{"AB_123", 0, false},
+		{"X", 0, false},
+	} {
+		if v, ok := fm.LookupString(tc.key); v != tc.value || ok != tc.ok {
+			t.Errorf("LookupString %q: got %d, %v want %d, %v", tc.key, v, ok, tc.value, tc.ok)
+		}
+		if v, ok := fm.LookupBytes([]byte(tc.key)); v != tc.value || ok != tc.ok {
+			t.Errorf("LookupBytes %q: got %d, %v want %d, %v", tc.key, v, ok, tc.value, tc.ok)
+		}
+	}
+}
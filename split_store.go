@@ -0,0 +1,141 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "unsafe"
+
+// SplitUint32Store is a fast read only map from string to uint32 like
+// Uint32Store except that the values are held apart from the trie, in a
+// slice with one entry per key rather than one per node. Each node takes
+// 8 bytes rather than 12, and most nodes are not keys, so the map is
+// usually around a third smaller. Lookups need a rank query to find the
+// value once the key is found, which makes them a little slower.
+// The zero value is an empty map.
+type SplitUint32Store struct {
+	nodes    []setNode
+	terminal bitVector // whether each node is a key
+	values   []uint32  // value for each key in node order
+}
+
+// NewSplitUint32Store creates from the data supplied in src
+func NewSplitUint32Store(src Uint32Source) SplitUint32Store {
+	return SplitValues(NewUint32Store(src))
+}
+
+// SplitValues creates a SplitUint32Store with the same contents as m,
+// which is not modified
+func SplitValues(m Uint32Store) SplitUint32Store {
+	m = m.rooted()
+	if len(m.store) == 0 {
+		return SplitUint32Store{}
+	}
+	s := SplitUint32Store{
+		nodes:  make([]setNode, len(m.store)),
+		values: make([]uint32, 0, m.Len()),
+	}
+	for i := range m.store {
+		bv := &m.store[i]
+		s.nodes[i] = setNode{nextLo: bv.nextLo, nextLen: bv.nextLen, nextOffset: bv.nextOffset, valid: bv.valid}
+		s.terminal.push(bv.valid)
+		if bv.valid {
+			s.values = append(s.values, bv.value)
+		}
+	}
+	s.terminal.finish()
+	return s
+}
+
+// value returns the value for node i, which must be a key
+func (s *SplitUint32Store) value(i uint32) uint32 {
+	return s.values[s.terminal.rank1(int(i))]
+}
+
+// LookupString looks up the supplied string in the map
+func (s *SplitUint32Store) LookupString(k string) (uint32, bool) {
+	if len(s.nodes) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	nd := &s.nodes[0]
+	for j, n := 0, len(k); j < n; j++ {
+		b := k[j]
+		if b < nd.nextOffset {
+			return 0, false
+		}
+		ni := b - nd.nextOffset
+		if uint16(ni) >= nd.nextLen {
+			return 0, false
+		}
+		i = nd.nextLo + uint32(ni)
+		nd = &s.nodes[i]
+	}
+	if !nd.valid {
+		return 0, false
+	}
+	return s.value(i), true
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (s *SplitUint32Store) LookupBytes(k []byte) (uint32, bool) {
+	if len(s.nodes) == 0 {
+		return 0, false
+	}
+	i := uint32(0)
+	nd := &s.nodes[0]
+	for _, b := range k {
+		if b < nd.nextOffset {
+			return 0, false
+		}
+		ni := b - nd.nextOffset
+		if uint16(ni) >= nd.nextLen {
+			return 0, false
+		}
+		i = nd.nextLo + uint32(ni)
+		nd = &s.nodes[i]
+	}
+	if !nd.valid {
+		return 0, false
+	}
+	return s.value(i), true
+}
+
+// Len returns the number of keys in the map
+func (s *SplitUint32Store) Len() int { return len(s.values) }
+
+// Walk calls fn for each key in the map in sorted order until fn returns
+// false. It uses an explicit stack, like Uint32Store.Walk, so that the
+// length of the keys does not bound the depth of the Go stack.
+func (s *SplitUint32Store) Walk(fn func(string, uint32) bool) {
+	if len(s.nodes) == 0 {
+		return
+	}
+	var frames [walkStackSize]iterFrame
+	stack := append(frames[:0], iterFrame{node: 0, next: -1})
+	key := make([]byte, 0, 256)
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		nd := &s.nodes[f.node]
+		switch {
+		case f.next < 0:
+			f.next = 0
+			if nd.valid && !fn(string(key), s.value(f.node)) {
+				return
+			}
+		case f.next < int(nd.nextLen):
+			j := f.next
+			f.next++
+			stack = append(stack, iterFrame{node: nd.nextLo + uint32(j), next: -1})
+			key = append(key, nd.nextOffset+byte(j))
+		default:
+			if stack = stack[:len(stack)-1]; len(stack) > 0 {
+				key = key[:len(key)-1]
+			}
+		}
+	}
+}
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the SplitUint32Store value itself
+func (s *SplitUint32Store) SizeInBytes() int {
+	return cap(s.nodes)*int(unsafe.Sizeof(setNode{})) + s.terminal.sizeInBytes() + cap(s.values)*4
+}
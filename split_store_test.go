@@ -0,0 +1,42 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestSplitUint32Store(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	full := faststringmap.NewUint32Store(ms)
+	s := faststringmap.SplitValues(full)
+	checkLookuper(t, "random", &s, ms)
+	if s.SizeInBytes()*4 > full.SizeInBytes()*3 {
+		t.Errorf("split %d bytes not smaller than %d", s.SizeInBytes(), full.SizeInBytes())
+	}
+
+	s = faststringmap.NewSplitUint32Store(mapSliceN(map[string]uint32{"": 1, "a": 2, "ab": 3}, 3))
+	if v, ok := s.LookupString(""); !ok || v != 1 {
+		t.Errorf("empty key: got %d, %v want 1, true", v, ok)
+	}
+
+	var zero faststringmap.SplitUint32Store
+	checkLookuper(t, "zero", &zero, mapSlice{out: []string{"", "a"}})
+	s = faststringmap.NewSplitUint32Store(mapSlice{})
+	checkLookuper(t, "empty", &s, mapSlice{out: []string{"", "a"}})
+}
+
+func BenchmarkSplitUint32Store(b *testing.B) {
+	m := randomSmallStrings(1000, 8)
+	ms := mapSliceN(m, len(m))
+	s := faststringmap.NewSplitUint32Store(ms)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range ms.in {
+			s.LookupString(k)
+		}
+	}
+}
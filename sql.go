@@ -0,0 +1,40 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// SQLUint32Store is a Uint32Store which can be stored in and loaded
+// from a binary database column in its persisted form
+type SQLUint32Store struct {
+	Uint32Store
+}
+
+var (
+	_ driver.Valuer = SQLUint32Store{}
+	_ sql.Scanner   = (*SQLUint32Store)(nil)
+)
+
+// Value returns the persisted form of the map
+func (m SQLUint32Store) Value() (driver.Value, error) {
+	return m.MarshalBinary()
+}
+
+// Scan loads the map from the persisted form in src, which must be
+// []byte or string. A NULL value gives an empty map.
+func (m *SQLUint32Store) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		m.Uint32Store = newUint32StoreSorted(nil, nil)
+		return nil
+	case []byte:
+		return m.UnmarshalBinary(src)
+	case string:
+		return m.UnmarshalBinary([]byte(src))
+	}
+	return fmt.Errorf("faststringmap: cannot scan %T into SQLUint32Store", src)
+}
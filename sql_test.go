@@ -0,0 +1,51 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestSQLUint32Store(t *testing.T) {
+	m := randomSmallStrings(1000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	src := faststringmap.SQLUint32Store{Uint32Store: faststringmap.NewUint32Store(ms)}
+
+	v, err := src.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		t.Fatalf("Value returned %T want []byte", v)
+	}
+
+	for _, col := range []interface{}{b, string(b)} {
+		var dst faststringmap.SQLUint32Store
+		if err := dst.Scan(col); err != nil {
+			t.Fatal(err)
+		}
+		if dst.Fingerprint() != src.Fingerprint() {
+			t.Errorf("scanned %T map differs", col)
+		}
+	}
+
+	var dst faststringmap.SQLUint32Store
+	if err := dst.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Len() != 0 {
+		t.Errorf("NULL gave map with %d keys", dst.Len())
+	}
+	if err := dst.Scan(42); err == nil {
+		t.Error("no error scanning int")
+	}
+
+	b[len(b)-1]++
+	if err := dst.Scan(b); !errors.Is(err, faststringmap.ErrInvalidData) {
+		t.Errorf("got error %v want %v", err, faststringmap.ErrInvalidData)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "unsafe"
+
+// Thresholds for NewTwoByteRootStore to build the dispatch table: either
+// a fraction of the table must lead to keys or the table must be small
+// compared with the store
+const (
+	twoByteRootMinFill     = 0.25
+	twoByteRootMaxOverhead = 0.125
+)
+
+// TwoByteRootStore is a Uint32Store with a table giving the byteValue for
+// the first two bytes of a string in a single step. This removes two
+// dependent memory accesses from lookups of strings of two or more bytes,
+// which pays off when the first two bytes of keys are dense, for example
+// numeric codes.
+type TwoByteRootStore struct {
+	m        Uint32Store
+	lo0, lo1 byte     // lowest first and second bytes
+	n0, n1   uint32   // number of first and second bytes covered by table
+	table    []uint32 // store index for each pair of bytes, 0 if none
+}
+
+// NewTwoByteRootStore creates a TwoByteRootStore for m. It returns false,
+// and a TwoByteRootStore which behaves the same as m, if the first two bytes
+// of the keys are too sparse for the table to be worthwhile. For very large
+// maps the table, of up to 65536 entries, is built however sparse it is as
+// long as it is small compared with the store.
+func NewTwoByteRootStore(m Uint32Store) (TwoByteRootStore, bool) {
+	m = m.rooted()
+	t := TwoByteRootStore{m: m}
+	if len(m.store) == 0 {
+		return t, false
+	}
+	root := &m.store[0]
+	if root.nextLen == 0 {
+		return t, false
+	}
+	// find the range of second bytes across all first bytes
+	lo1, hi1 := 255, -1
+	for j := uint32(0); j < uint32(root.nextLen); j++ {
+		if bv := &m.store[root.nextLo+j]; bv.nextLen > 0 {
+			if int(bv.nextOffset) < lo1 {
+				lo1 = int(bv.nextOffset)
+			}
+			if hi := int(bv.nextOffset) + int(bv.nextLen) - 1; hi > hi1 {
+				hi1 = hi
+			}
+		}
+	}
+	if hi1 < 0 {
+		return t, false
+	}
+	n0, n1 := uint32(root.nextLen), uint32(hi1-lo1+1)
+	table := make([]uint32, n0*n1)
+	used := 0
+	for j := uint32(0); j < n0; j++ {
+		bv := &m.store[root.nextLo+j]
+		for k := uint32(0); k < uint32(bv.nextLen); k++ {
+			i := bv.nextLo + k
+			if next := &m.store[i]; next.valid || next.nextLen > 0 {
+				table[j*n1+uint32(bv.nextOffset)-uint32(lo1)+k] = i
+				used++
+			}
+		}
+	}
+	if float64(used) < twoByteRootMinFill*float64(len(table)) &&
+		float64(len(table))*float64(unsafe.Sizeof(uint32(0))) > twoByteRootMaxOverhead*float64(m.SizeInBytes()) {
+		return t, false
+	}
+	t.lo0, t.lo1, t.n0, t.n1, t.table = root.nextOffset, byte(lo1), n0, n1, table
+	return t, true
+}
+
+// LookupString looks up the supplied string in the map
+func (t *TwoByteRootStore) LookupString(s string) (uint32, bool) {
+	if len(s) < 2 || t.table == nil {
+		return t.m.LookupString(s)
+	}
+	j, k := uint32(s[0]-t.lo0), uint32(s[1]-t.lo1)
+	if j >= t.n0 || k >= t.n1 {
+		return 0, false
+	}
+	i := t.table[j*t.n1+k]
+	if i == 0 {
+		return 0, false
+	}
+	bv := &t.m.store[i]
+	for p, n := 2, len(s); p < n; p++ {
+		b := s[p]
+		if b < bv.nextOffset {
+			return 0, false
+		}
+		ni := b - bv.nextOffset
+		if uint16(ni) >= bv.nextLen {
+			return 0, false
+		}
+		bv = &t.m.store[bv.nextLo+uint32(ni)]
+	}
+	return bv.value, bv.valid
+}
+
+// LookupBytes looks up the supplied byte slice in the map
+func (t *TwoByteRootStore) LookupBytes(s []byte) (uint32, bool) {
+	if len(s) < 2 || t.table == nil {
+		return t.m.LookupBytes(s)
+	}
+	j, k := uint32(s[0]-t.lo0), uint32(s[1]-t.lo1)
+	if j >= t.n0 || k >= t.n1 {
+		return 0, false
+	}
+	i := t.table[j*t.n1+k]
+	if i == 0 {
+		return 0, false
+	}
+	bv := &t.m.store[i]
+	for _, b := range s[2:] {
+		if b < bv.nextOffset {
+			return 0, false
+		}
+		ni := b - bv.nextOffset
+		if uint16(ni) >= bv.nextLen {
+			return 0, false
+		}
+		bv = &t.m.store[bv.nextLo+uint32(ni)]
+	}
+	return bv.value, bv.valid
+}
+
+// Len returns the number of keys in the map
+func (t *TwoByteRootStore) Len() int { return t.m.Len() }
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (t *TwoByteRootStore) Walk(fn func(string, uint32) bool) { t.m.Walk(fn) }
@@ -0,0 +1,54 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestTwoByteRootStore(t *testing.T) {
+	ms := typicalCodeStrings(1000)
+	ms.out = []string{"", "0", "00", "1000", "-", "-8", "9a", "~~", "12345"}
+	tb, ok := faststringmap.NewTwoByteRootStore(faststringmap.NewUint32Store(ms))
+	if !ok {
+		t.Fatal("table not built for dense codes")
+	}
+	checkLookuper(t, "TwoByteRootStore", &tb, ms)
+
+	sparse := mapSliceN(map[string]uint32{"a!": 1, "z~": 2, "b": 3}, 3)
+	tb, ok = faststringmap.NewTwoByteRootStore(faststringmap.NewUint32Store(sparse))
+	if ok {
+		t.Error("table built for sparse keys")
+	}
+	checkLookuper(t, "sparse TwoByteRootStore", &tb, sparse)
+
+	// a large map with sparse first two bytes
+	large := map[string]uint32{}
+	for len(large) < 50000 {
+		k := string("az"[len(large)%2]) + string("!~"[len(large)/2%2]) + randomSmallString(12)
+		large[k] = uint32(len(large))
+	}
+	ms = mapSliceN(large, len(large)/2)
+	ms.out = append(ms.out, "a", "a!", "b!", "a}")
+	tb, ok = faststringmap.NewTwoByteRootStore(faststringmap.NewUint32Store(ms))
+	if !ok {
+		t.Error("table not built for large map")
+	}
+	checkLookuper(t, "large TwoByteRootStore", &tb, ms)
+}
+
+func BenchmarkTwoByteRootStore(b *testing.B) {
+	m := typicalCodeStrings(nStrsBench)
+	fm, _ := faststringmap.NewTwoByteRootStore(faststringmap.NewUint32Store(m))
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		for si, n := uint32(0), uint32(len(m.in)); si < n; si++ {
+			v, ok := fm.LookupString(m.in[si])
+			if !ok || v != si {
+				b.Fatalf("ok=%v, value got %d want %d", ok, v, si)
+			}
+		}
+	}
+}
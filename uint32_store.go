@@ -4,19 +4,29 @@
 package faststringmap
 
 import (
+	"fmt"
 	"sort"
 )
 
 type (
 	// Uint32Store is a fast read only map from string to uint32
 	// Lookups are about 5x faster than the built-in Go map type
+	// The zero value is an empty map
+	//
+	// Copies of a Uint32Store, and the maps made from it by WithKey and
+	// WithoutKey, share its store. They also share a count of the store in
+	// use, so that only one of them at a time can add nodes in place at the
+	// end of the store and the others copy it instead.
 	Uint32Store struct {
 		store []byteValue
+		n     int     // number of keys
+		root  uint32  // index in store of the root, only non-zero after WithKey or WithoutKey
+		tail  *uint32 // length in use of the array behind store, shared by maps made by WithKey or WithoutKey
 	}
 
 	byteValue struct {
 		nextLo     uint32 // index in store of next byteValues
-		nextLen    byte   // number of byteValues in store used for next possible bytes
+		nextLen    uint16 // number of byteValues in store used for next possible bytes, up to 256
 		nextOffset byte   // offset from zero byte value of first element of range of byteValues
 		valid      bool   // is the byte sequence with no more bytes in the map?
 		value      uint32 // value for byte sequence with no more bytes
@@ -32,72 +42,135 @@ type (
 
 	// uint32Builder is used only during construction
 	uint32Builder struct {
-		all [][]byteValue
-		src Uint32Source
-		len int
+		all    [][]byteValue
+		keys   []string
+		value  func(i int) uint32 // value for keys[i]
+		len    int
+		report *buildReporter // nil unless progress is reported
+		keysIn int            // keys processed since last passed to report
+		lenIn  int            // len when last passed to report
+		maxLen uint64         // limit on len, or zero for no limit
+		reuse  []byteValue    // store to build into if it has enough capacity
+	}
+
+	// buildTask is a byteValue still to be initialised by makeByteValue
+	buildTask struct {
+		bv        *byteValue
+		lo, hi    int // range of keys
+		byteIndex int
 	}
 )
 
 // NewUint32Store creates from the data supplied in src
 func NewUint32Store(src Uint32Source) Uint32Store {
-	if keys := src.AppendKeys([]string(nil)); len(keys) > 0 {
-		sort.Strings(keys)
-		return Uint32Store{store: uint32Build(keys, src)}
+	keys := src.AppendKeys([]string(nil))
+	sort.Strings(keys)
+	return newUint32StoreSorted(keys, func(i int) uint32 { return src.Get(keys[i]) })
+}
+
+// newUint32StoreSorted creates from the sorted keys with no duplicates
+// and a function giving the value for keys[i]
+func newUint32StoreSorted(keys []string, value func(i int) uint32) Uint32Store {
+	return newUint32StoreReporting(keys, value, nil)
+}
+
+// newUint32StoreReporting is like newUint32StoreSorted but reports
+// progress to r unless it is nil
+func newUint32StoreReporting(keys []string, value func(i int) uint32, r *buildReporter) Uint32Store {
+	if len(keys) > 0 {
+		return Uint32Store{store: uint32BuildReporting(keys, value, r), n: len(keys)}
 	}
 	return Uint32Store{store: []byteValue{{}}}
 }
 
-// uint32Build constructs the map by allocating memory in blocks
-// and then copying into the eventual slice at the end. This is
-// more efficient than continually using append.
-func uint32Build(keys []string, src Uint32Source) []byteValue {
-	b := uint32Builder{
-		all: [][]byteValue{make([]byteValue, 1, firstBufSize(len(keys)))},
-		src: src,
-		len: 1,
+// uint32Build constructs the map for the sorted keys by allocating
+// memory in blocks and then copying into the eventual slice at the end.
+// This is more efficient than continually using append.
+func uint32Build(keys []string, value func(i int) uint32) []byteValue {
+	return uint32BuildReporting(keys, value, nil)
+}
+
+// uint32BuildReporting is like uint32Build but reports progress to r
+// unless it is nil
+func uint32BuildReporting(keys []string, value func(i int) uint32, r *buildReporter) []byteValue {
+	b := uint32Builder{keys: keys, value: value, report: r}
+	return b.build()
+}
+
+// build constructs the map for the sorted keys in b.keys
+func (b *uint32Builder) build() []byteValue {
+	b.all = [][]byteValue{getBuildBlock(1, firstBufSize(len(b.keys)))}
+	b.len = 1
+	b.makeByteValues(&b.all[0][0])
+	if b.report != nil {
+		b.report.add(b.keysIn, b.len-b.lenIn)
 	}
-	b.makeByteValue(&b.all[0][0], keys, 0)
 	// copy all blocks to one slice
-	s := make([]byteValue, 0, b.len)
+	s := b.reuse[:0]
+	if cap(s) < b.len {
+		s = make([]byteValue, 0, b.len)
+	}
 	for _, a := range b.all {
 		s = append(s, a...)
+		putBuildBlock(a)
 	}
 	return s
 }
 
-// makeByteValue will initialise the supplied byteValue for
-// the sorted strings in slice a considering bytes at byteIndex in the strings
-func (b *uint32Builder) makeByteValue(bv *byteValue, a []string, byteIndex int) {
+// makeByteValues will initialise the supplied byteValue, and those below
+// it, for the sorted strings in keys. The nodes are made in the same order
+// as a depth first recursion would make them, but using an explicit stack
+// so that the length of the keys does not bound the depth of the Go stack.
+func (b *uint32Builder) makeByteValues(bv *byteValue) {
+	stack := []buildTask{{bv: bv, hi: len(b.keys)}}
+	for len(stack) > 0 {
+		t := stack[len(stack)-1]
+		stack = b.makeByteValue(stack[:len(stack)-1], t)
+	}
+}
+
+// makeByteValue will initialise t.bv for the sorted strings in
+// keys[t.lo:t.hi] considering bytes at t.byteIndex, pushing a task onto
+// stack for each of its next byteValues with the lowest byte on top
+func (b *uint32Builder) makeByteValue(stack []buildTask, t buildTask) []buildTask {
+	a, bv, lo, hi, byteIndex := b.keys, t.bv, t.lo, t.hi, t.byteIndex
 	// if there is a string with no more bytes then it is always first because they are sorted
-	if len(a[0]) == byteIndex {
+	if len(a[lo]) == byteIndex {
 		bv.valid = true
-		bv.value = b.src.Get(a[0])
-		a = a[1:]
+		bv.value = b.value(lo)
+		lo++
+		if b.report != nil {
+			b.reportKey()
+		}
 	}
-	if len(a) == 0 {
-		return
+	if lo == hi {
+		return stack
 	}
-	bv.nextOffset = a[0][byteIndex]       // lowest value for next byte
-	bv.nextLen = a[len(a)-1][byteIndex] - // highest value for next byte
-		bv.nextOffset + 1 // minus lowest value +1 = number of possible next bytes
+	bv.nextOffset = a[lo][byteIndex]          // lowest value for next byte
+	bv.nextLen = uint16(a[hi-1][byteIndex]) - // highest value for next byte
+		uint16(bv.nextOffset) + 1 // minus lowest value +1 = number of possible next bytes
 	bv.nextLo = uint32(b.len)   // first byteValue struct in eventual built slice
 	next := b.alloc(bv.nextLen) // new byteValues default to "not valid"
 
-	for i, n := 0, len(a); i < n; {
-		// find range of strings starting with the same byte
-		iSameByteHi := i + 1
-		for iSameByteHi < n && a[iSameByteHi][byteIndex] == a[i][byteIndex] {
-			iSameByteHi++
+	for i := hi; i > lo; {
+		// find range of strings ending at i starting with the same byte
+		iSameByteLo := i - 1
+		for iSameByteLo > lo && a[iSameByteLo-1][byteIndex] == a[i-1][byteIndex] {
+			iSameByteLo--
 		}
-		b.makeByteValue(&next[(a[i][byteIndex]-bv.nextOffset)], a[i:iSameByteHi], byteIndex+1)
-		i = iSameByteHi
+		stack = append(stack, buildTask{
+			bv:        &next[(a[i-1][byteIndex] - bv.nextOffset)],
+			lo:        iSameByteLo,
+			hi:        i,
+			byteIndex: byteIndex + 1,
+		})
+		i = iSameByteLo
 	}
+	return stack
 }
 
-const maxBuildBufSize = 1 << 20
-
 func firstBufSize(mapSize int) int {
-	size := 1 << 4
+	size := minBuildBufSize
 	for size < mapSize && size < maxBuildBufSize {
 		size <<= 1
 	}
@@ -105,9 +178,12 @@ func firstBufSize(mapSize int) int {
 }
 
 // alloc will grab space in the current block if available or allocate a new one if not
-func (b *uint32Builder) alloc(nByteValues byte) []byteValue {
+func (b *uint32Builder) alloc(nByteValues uint16) []byteValue {
 	n := int(nByteValues)
 	b.len += n
+	if b.maxLen > 0 && uint64(b.len) > b.maxLen {
+		panic(buildAbort{fmt.Errorf("%w: more than limit of %d nodes", ErrTooLarge, b.maxLen)})
+	}
 	cur := &b.all[len(b.all)-1] // current
 	curCap, curLen := cap(*cur), len(*cur)
 	if curCap-curLen >= n { // enough space in current
@@ -121,21 +197,24 @@ func (b *uint32Builder) alloc(nByteValues byte) []byteValue {
 	if newCap > maxBuildBufSize {
 		newCap = maxBuildBufSize
 	}
-	a := make([]byteValue, n, newCap)
+	a := getBuildBlock(n, newCap)
 	b.all = append(b.all, a)
 	return a
 }
 
 // LookupString looks up the supplied string in the map
 func (m *Uint32Store) LookupString(s string) (uint32, bool) {
-	bv := &m.store[0]
+	if len(m.store) == 0 {
+		return 0, false
+	}
+	bv := &m.store[m.root]
 	for i, n := 0, len(s); i < n; i++ {
 		b := s[i]
 		if b < bv.nextOffset {
 			return 0, false
 		}
 		ni := b - bv.nextOffset
-		if ni >= bv.nextLen {
+		if uint16(ni) >= bv.nextLen {
 			return 0, false
 		}
 		bv = &m.store[bv.nextLo+uint32(ni)]
@@ -145,16 +224,92 @@ func (m *Uint32Store) LookupString(s string) (uint32, bool) {
 
 // LookupBytes looks up the supplied byte slice in the map
 func (m *Uint32Store) LookupBytes(s []byte) (uint32, bool) {
-	bv := &m.store[0]
+	if len(m.store) == 0 {
+		return 0, false
+	}
+	bv := &m.store[m.root]
 	for _, b := range s {
 		if b < bv.nextOffset {
 			return 0, false
 		}
 		ni := b - bv.nextOffset
-		if ni >= bv.nextLen {
+		if uint16(ni) >= bv.nextLen {
 			return 0, false
 		}
 		bv = &m.store[bv.nextLo+uint32(ni)]
 	}
 	return bv.value, bv.valid
 }
+
+// Lookup2 looks up the concatenation of a and b in the map
+// without allocating the concatenated string
+func (m *Uint32Store) Lookup2(a, b string) (uint32, bool) {
+	if len(m.store) == 0 {
+		return 0, false
+	}
+	if i, ok := m.follow(m.root, a); ok {
+		if i, ok = m.follow(i, b); ok {
+			bv := &m.store[i]
+			return bv.value, bv.valid
+		}
+	}
+	return 0, false
+}
+
+// LookupJoin looks up the parts joined with the separator sep in the map
+// without allocating the joined string
+func (m *Uint32Store) LookupJoin(parts []string, sep byte) (uint32, bool) {
+	if len(m.store) == 0 {
+		return 0, false
+	}
+	i := m.root
+	for pi, p := range parts {
+		ok := true
+		if pi > 0 {
+			i, ok = m.step(i, sep)
+		}
+		if ok {
+			i, ok = m.follow(i, p)
+		}
+		if !ok {
+			return 0, false
+		}
+	}
+	bv := &m.store[i]
+	return bv.value, bv.valid
+}
+
+// follow returns the index in store reached by following the bytes of s
+// starting from the byteValue at index i
+func (m *Uint32Store) follow(i uint32, s string) (uint32, bool) {
+	for j, n := 0, len(s); j < n; j++ {
+		var ok bool
+		if i, ok = m.step(i, s[j]); !ok {
+			return 0, false
+		}
+	}
+	return i, true
+}
+
+// step returns the index in store of the next byteValue for byte b
+// following the byteValue at index i
+func (m *Uint32Store) step(i uint32, b byte) (uint32, bool) {
+	bv := &m.store[i]
+	if b < bv.nextOffset {
+		return 0, false
+	}
+	ni := b - bv.nextOffset
+	if uint16(ni) >= bv.nextLen {
+		return 0, false
+	}
+	return bv.nextLo + uint32(ni), true
+}
+
+// funcSource is a Uint32Source for a slice of keys and a function giving their values
+type funcSource struct {
+	keys []string
+	get  func(string) uint32
+}
+
+func (s funcSource) AppendKeys(a []string) []string { return append(a, s.keys...) }
+func (s funcSource) Get(k string) uint32            { return s.get(k) }
@@ -0,0 +1,79 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// ApproxMatch is a key found by LookupApprox
+type ApproxMatch struct {
+	Key   string
+	Value uint32
+	Dist  int // Levenshtein distance from the string looked up
+}
+
+// LookupApprox returns the keys in the map within Levenshtein distance
+// maxDist of s, counting each byte inserted, deleted or substituted as
+// one, in sorted key order. A row of the edit distance table is computed
+// for each node visited, and sub-tries are skipped as soon as every entry
+// in the row exceeds maxDist, so only a small part of the trie is visited
+// for small distances.
+func (m *Uint32Store) LookupApprox(s string, maxDist int) []ApproxMatch {
+	if len(m.store) == 0 || maxDist < 0 {
+		return nil
+	}
+	a := approxSearch{m: m, s: s, maxDist: maxDist}
+	row := a.row(0)
+	for j := range row {
+		row[j] = j
+	}
+	if bv := &m.store[m.root]; bv.valid && len(s) <= maxDist {
+		a.matches = append(a.matches, ApproxMatch{Value: bv.value, Dist: len(s)})
+	}
+	a.from(m.root, make([]byte, 0, len(s)+maxDist))
+	return a.matches
+}
+
+// approxSearch is used only during LookupApprox
+type approxSearch struct {
+	m       *Uint32Store
+	s       string
+	maxDist int
+	rows    [][]int // edit distance rows for each depth
+	matches []ApproxMatch
+}
+
+// row returns the edit distance row for keys of length depth
+func (a *approxSearch) row(depth int) []int {
+	for len(a.rows) <= depth {
+		a.rows = append(a.rows, make([]int, len(a.s)+1))
+	}
+	return a.rows[depth]
+}
+
+// from searches the sub-trie after node i where key leads to node i and
+// the edit distance row for key has been computed
+func (a *approxSearch) from(i uint32, key []byte) {
+	bv := a.m.store[i]
+	prev := a.row(len(key))
+	for j := uint32(0); j < uint32(bv.nextLen); j++ {
+		c := bv.nextOffset + byte(j)
+		row := a.row(len(key) + 1)
+		row[0] = prev[0] + 1
+		best := row[0]
+		for k := 1; k <= len(a.s); k++ {
+			d := prev[k-1]
+			if a.s[k-1] != c {
+				d++
+			}
+			d = minInt(d, minInt(prev[k], row[k-1])+1)
+			row[k] = d
+			best = minInt(best, d)
+		}
+		if best > a.maxDist {
+			continue
+		}
+		next := bv.nextLo + j
+		if nbv := &a.m.store[next]; nbv.valid && row[len(a.s)] <= a.maxDist {
+			a.matches = append(a.matches, ApproxMatch{Key: string(append(key, c)), Value: nbv.value, Dist: row[len(a.s)]})
+		}
+		a.from(next, append(key, c))
+	}
+}
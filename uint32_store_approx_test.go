@@ -0,0 +1,92 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestLookupApprox(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"":       0,
+		"code":   1,
+		"coder":  2,
+		"cold":   3,
+		"decode": 4,
+		"node":   5,
+		"xyz":    6,
+	})
+	for _, tc := range []struct {
+		s       string
+		maxDist int
+		want    []faststringmap.ApproxMatch
+	}{
+		{"code", 0, []faststringmap.ApproxMatch{{"code", 1, 0}}},
+		{"code", 1, []faststringmap.ApproxMatch{{"code", 1, 0}, {"coder", 2, 1}, {"node", 5, 1}}},
+		{"cdoe", 2, []faststringmap.ApproxMatch{{"code", 1, 2}}},
+		{"cod", 1, []faststringmap.ApproxMatch{{"code", 1, 1}, {"cold", 3, 1}}},
+		{"ab", 2, []faststringmap.ApproxMatch{{"", 0, 2}}},
+		{"qqqqqqq", 2, nil},
+		{"code", -1, nil},
+	} {
+		if got := fm.LookupApprox(tc.s, tc.maxDist); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q %d: got %v want %v", tc.s, tc.maxDist, got, tc.want)
+		}
+	}
+
+	// check against a brute force search
+	m := randomSmallStrings(2000, 5)
+	fm = faststringmap.NewUint32StoreFromMap(m)
+	ms := mapSliceN(m, len(m))
+	for _, s := range ms.in[:50] {
+		got := fm.LookupApprox(s, 2)
+		n := 0
+		for _, k := range ms.in {
+			if levenshtein(s, k) <= 2 {
+				n++
+			}
+		}
+		if len(got) != n {
+			t.Errorf("%q: got %d matches want %d", s, len(got), n)
+		}
+		for _, am := range got {
+			if d := levenshtein(s, am.Key); d != am.Dist || m[am.Key] != am.Value {
+				t.Errorf("%q: got %v want distance %d value %d", s, am, d, m[am.Key])
+			}
+		}
+	}
+
+	var zero faststringmap.Uint32Store
+	if got := zero.LookupApprox("a", 3); got != nil {
+		t.Errorf("zero got %v", got)
+	}
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		row := make([]int, len(b)+1)
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			d := prev[j-1]
+			if a[i-1] != b[j-1] {
+				d++
+			}
+			if prev[j]+1 < d {
+				d = prev[j] + 1
+			}
+			if row[j-1]+1 < d {
+				d = row[j-1] + 1
+			}
+			row[j] = d
+		}
+		prev = row
+	}
+	return prev[len(b)]
+}
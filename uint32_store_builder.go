@@ -0,0 +1,101 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrDuplicateKey is returned (wrapped) when the same key is added more than once
+var ErrDuplicateKey = errors.New("faststringmap: duplicate key")
+
+// builderShards is the number of independently locked buffers in a
+// Uint32StoreBuilder, so concurrent calls to Add rarely contend
+const builderShards = 16
+
+type (
+	// Uint32StoreBuilder accumulates keys and values to create a Uint32Store
+	// as an alternative to implementing Uint32Source. It is safe for
+	// concurrent use by multiple goroutines. The zero value is ready to use.
+	Uint32StoreBuilder struct {
+		shards        [builderShards]builderShard
+		progress      func(BuildProgress) // set by SetProgress
+		progressEvery int
+	}
+
+	builderShard struct {
+		mu      sync.Mutex
+		entries []builderEntry
+		_       [32]byte // keep shards on separate cache lines
+	}
+
+	builderEntry struct {
+		key   string
+		value uint32
+	}
+)
+
+// Add adds key k with value v
+func (b *Uint32StoreBuilder) Add(k string, v uint32) {
+	sh := &b.shards[shardOf(k)]
+	sh.mu.Lock()
+	sh.entries = append(sh.entries, builderEntry{key: k, value: v})
+	sh.mu.Unlock()
+}
+
+// AddBytes adds key k, which is copied, with value v, for data from a
+// parser or network stream which reuses its buffers
+func (b *Uint32StoreBuilder) AddBytes(k []byte, v uint32) { b.Add(string(k), v) }
+
+// Len returns the number of keys added so far, counting duplicates
+func (b *Uint32StoreBuilder) Len() int {
+	n := 0
+	for i := range b.shards {
+		sh := &b.shards[i]
+		sh.mu.Lock()
+		n += len(sh.entries)
+		sh.mu.Unlock()
+	}
+	return n
+}
+
+// Build creates a Uint32Store from the keys and values added so far.
+// It returns an error wrapping ErrDuplicateKey if a key was added more than once.
+func (b *Uint32StoreBuilder) Build() (Uint32Store, error) {
+	start := time.Now()
+	var entries []builderEntry
+	for i := range b.shards {
+		sh := &b.shards[i]
+		sh.mu.Lock()
+		entries = append(entries, sh.entries...)
+		sh.mu.Unlock()
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		if i > 0 && e.key == keys[i-1] {
+			return Uint32Store{}, fmt.Errorf("%w: %q", ErrDuplicateKey, e.key)
+		}
+		keys[i] = e.key
+	}
+	r := b.newReporter(start, len(keys))
+	m := newUint32StoreReporting(keys, func(i int) uint32 { return entries[i].value }, r)
+	if r != nil {
+		r.finish(len(m.store))
+	}
+	return m, nil
+}
+
+// shardOf returns the shard for key k using FNV-1a
+func shardOf(k string) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(k); i++ {
+		h ^= uint32(k[i])
+		h *= 16777619
+	}
+	return int(h % builderShards)
+}
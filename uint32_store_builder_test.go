@@ -0,0 +1,77 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestUint32StoreBuilder(t *testing.T) {
+	m := randomSmallStrings(4096, 8)
+	ms := mapSliceN(m, len(m)/2)
+
+	var b faststringmap.Uint32StoreBuilder
+	var wg sync.WaitGroup
+	const nWorkers = 8
+	for w := 0; w < nWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(ms.in); i += nWorkers {
+				b.Add(ms.in[i], m[ms.in[i]])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	fm, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := faststringmap.NewUint32Store(ms)
+	if fm.Fingerprint() != want.Fingerprint() {
+		t.Error("built map differs from NewUint32Store")
+	}
+
+	b.Add(ms.in[0], 0)
+	if _, err := b.Build(); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("got error %v want %v", err, faststringmap.ErrDuplicateKey)
+	}
+}
+
+func TestUint32StoreBuilderEmpty(t *testing.T) {
+	var b faststringmap.Uint32StoreBuilder
+	fm, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := fm.LookupString(""); ok {
+		t.Errorf("empty string present when not expected, got %d", v)
+	}
+}
+
+func TestUint32StoreBuilderAddBytes(t *testing.T) {
+	var b faststringmap.Uint32StoreBuilder
+	buf := []byte("key0")
+	for i := byte(0); i < 5; i++ {
+		buf[3] = '0' + i // reused buffer as from a parser
+		b.AddBytes(buf, uint32(i))
+	}
+	if b.Len() != 5 {
+		t.Errorf("Len got %d want 5", b.Len())
+	}
+	fm, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		k := "key" + string(rune('0'+i))
+		if v, ok := fm.LookupString(k); !ok || v != uint32(i) {
+			t.Errorf("%q: got %d, %v want %d, true", k, v, ok, i)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"context"
+	"sort"
+)
+
+// ctxCheckEvery is how many comparisons while sorting, or keys while
+// building the trie, there are between checks of the context
+const ctxCheckEvery = 1 << 14
+
+type (
+	// buildAbort is panicked with to abandon a build, for example when its
+	// context is done, and recovered by recoverBuildAbort
+	buildAbort struct{ err error }
+
+	// ctxStrings sorts strings, checking a context every ctxCheckEvery comparisons
+	ctxStrings struct {
+		ctx context.Context
+		a   []string
+		n   int // comparisons since the last check
+	}
+)
+
+// NewUint32StoreCtx creates from the data supplied in src like
+// NewUint32Store but checks ctx periodically while sorting the keys and
+// building the trie, returning ctx.Err() if it is done before the map is
+// complete, so that a rebuild for a request can be cancelled with it
+func NewUint32StoreCtx(ctx context.Context, src Uint32Source) (m Uint32Store, err error) {
+	if err := ctx.Err(); err != nil {
+		return Uint32Store{}, err
+	}
+	defer recoverBuildAbort(&m, &err)
+	keys := src.AppendKeys([]string(nil))
+	sort.Sort(&ctxStrings{ctx: ctx, a: keys})
+	r := &buildReporter{
+		fn:    func(BuildProgress) { checkCtx(ctx) },
+		every: ctxCheckEvery,
+		p:     BuildProgress{TotalKeys: len(keys)},
+		next:  ctxCheckEvery,
+	}
+	return newUint32StoreReporting(keys, func(i int) uint32 { return src.Get(keys[i]) }, r), nil
+}
+
+// checkCtx panics with buildAbort if ctx is done
+func checkCtx(ctx context.Context) {
+	if err := ctx.Err(); err != nil {
+		panic(buildAbort{err})
+	}
+}
+
+// recoverBuildAbort, when deferred, recovers from a panic with buildAbort
+// setting *m to an empty map and *err to the error, and passes on any other panic
+func recoverBuildAbort(m *Uint32Store, err *error) {
+	if r := recover(); r != nil {
+		a, ok := r.(buildAbort)
+		if !ok {
+			panic(r)
+		}
+		*m, *err = Uint32Store{}, a.err
+	}
+}
+
+func (s *ctxStrings) Len() int      { return len(s.a) }
+func (s *ctxStrings) Swap(i, j int) { s.a[i], s.a[j] = s.a[j], s.a[i] }
+
+func (s *ctxStrings) Less(i, j int) bool {
+	if s.n++; s.n == ctxCheckEvery {
+		s.n = 0
+		checkCtx(s.ctx)
+	}
+	return s.a[i] < s.a[j]
+}
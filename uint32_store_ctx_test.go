@@ -0,0 +1,66 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+// cancellingSource cancels a context after AppendKeys or after the
+// given number of calls to Get
+type cancellingSource struct {
+	faststringmap.Uint32MapSource
+	cancel       context.CancelFunc
+	onAppendKeys bool
+	gets         int
+}
+
+func (s *cancellingSource) AppendKeys(a []string) []string {
+	if s.onAppendKeys {
+		s.cancel()
+	}
+	return s.Uint32MapSource.AppendKeys(a)
+}
+
+func (s *cancellingSource) Get(k string) uint32 {
+	if s.gets--; s.gets == 0 {
+		s.cancel()
+	}
+	return s.Uint32MapSource.Get(k)
+}
+
+func TestNewUint32StoreCtx(t *testing.T) {
+	m := make(map[string]uint32, 50000)
+	for i := 0; i < 50000; i++ {
+		m[strconv.Itoa(i*7919)] = uint32(i)
+	}
+	fm, err := faststringmap.NewUint32StoreCtx(context.Background(), faststringmap.Uint32MapSource(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := faststringmap.NewUint32StoreFromMap(m); fm.Fingerprint() != want.Fingerprint() {
+		t.Error("map differs from NewUint32Store")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := faststringmap.NewUint32StoreCtx(ctx, faststringmap.Uint32MapSource(m)); !errors.Is(err, context.Canceled) {
+		t.Errorf("already cancelled: got error %v want %v", err, context.Canceled)
+	}
+
+	for name, src := range map[string]*cancellingSource{
+		"sort":  {Uint32MapSource: m, onAppendKeys: true},
+		"build": {Uint32MapSource: m, gets: 100},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		src.cancel = cancel
+		if _, err := faststringmap.NewUint32StoreCtx(ctx, src); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: got error %v want %v", name, err, context.Canceled)
+		}
+	}
+}
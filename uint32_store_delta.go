@@ -0,0 +1,150 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// delta stream format: deltaMagic, the fingerprints of the old and new
+// maps as little endian uint64s, then a sequence of records each starting
+// with an op byte, terminated by deltaEnd.
+//
+//	deltaSet:    uvarint key length, key, uvarint value
+//	deltaDelete: uvarint key length, key
+const (
+	deltaMagic  = "FSMD\x02"
+	deltaEnd    = 0
+	deltaSet    = 1
+	deltaDelete = 2
+)
+
+// WriteDelta writes the changes needed to turn old into cur to w,
+// which ApplyDelta can use to reconstruct cur from old. The delta
+// only holds the keys which were added, removed or changed value, and
+// the fingerprints of old and cur.
+func WriteDelta(w io.Writer, old, cur *Uint32Store) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(deltaMagic)
+	var buf [binary.MaxVarintLen64]byte
+	binary.LittleEndian.PutUint64(buf[:], old.Fingerprint())
+	bw.Write(buf[:8])
+	binary.LittleEndian.PutUint64(buf[:], cur.Fingerprint())
+	bw.Write(buf[:8])
+	writeRecord := func(op byte, key []byte) {
+		bw.WriteByte(op)
+		bw.Write(buf[:binary.PutUvarint(buf[:], uint64(len(key)))])
+		bw.Write(key)
+	}
+	cur.walk(func(key []byte, v uint32) bool {
+		if oldV, ok := old.LookupBytes(key); !ok || oldV != v {
+			writeRecord(deltaSet, key)
+			bw.Write(buf[:binary.PutUvarint(buf[:], uint64(v))])
+		}
+		return true
+	})
+	old.walk(func(key []byte, _ uint32) bool {
+		if _, ok := cur.LookupBytes(key); !ok {
+			writeRecord(deltaDelete, key)
+		}
+		return true
+	})
+	bw.WriteByte(deltaEnd)
+	return bw.Flush()
+}
+
+// ApplyDelta reads a delta written by WriteDelta from r and returns
+// the result of applying it to old, which is not modified. The error
+// wraps ErrInvalidData if the delta is malformed, if it was not made from
+// a map with the same contents as old, or if the result does not have the
+// contents of the map it was made for.
+func ApplyDelta(old *Uint32Store, r io.Reader) (Uint32Store, error) {
+	br := bufio.NewReader(r)
+	hdr := make([]byte, len(deltaMagic)+16)
+	if _, err := io.ReadFull(br, hdr); err != nil {
+		return Uint32Store{}, fmt.Errorf("%w: delta: %v", ErrInvalidData, noEOF(err))
+	}
+	if magic := hdr[:len(deltaMagic)]; string(magic) != deltaMagic {
+		return Uint32Store{}, fmt.Errorf("%w: delta: bad header %q", ErrInvalidData, magic)
+	}
+	oldFP := binary.LittleEndian.Uint64(hdr[len(deltaMagic):])
+	curFP := binary.LittleEndian.Uint64(hdr[len(deltaMagic)+8:])
+	if fp := old.Fingerprint(); fp != oldFP {
+		return Uint32Store{}, fmt.Errorf("%w: delta: made from map with fingerprint %#x not %#x", ErrInvalidData, oldFP, fp)
+	}
+
+	sets := make(map[string]uint32)
+	deletes := make(map[string]bool)
+	var key bytes.Buffer
+	for {
+		op, err := br.ReadByte()
+		if err != nil {
+			return Uint32Store{}, fmt.Errorf("%w: delta: %v", ErrInvalidData, noEOF(err))
+		}
+		if op == deltaEnd {
+			break
+		}
+		if op != deltaSet && op != deltaDelete {
+			return Uint32Store{}, fmt.Errorf("%w: delta: unknown op %d", ErrInvalidData, op)
+		}
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return Uint32Store{}, fmt.Errorf("%w: delta: %v", ErrInvalidData, noEOF(err))
+		}
+		// copy rather than allocate n bytes, which may be corrupt
+		key.Reset()
+		if _, err = io.CopyN(&key, br, int64(minUint64(n, 1<<62))); err != nil {
+			return Uint32Store{}, fmt.Errorf("%w: delta: %v", ErrInvalidData, noEOF(err))
+		}
+		if op == deltaDelete {
+			deletes[key.String()] = true
+			continue
+		}
+		v, err := binary.ReadUvarint(br)
+		if err != nil {
+			return Uint32Store{}, fmt.Errorf("%w: delta: %v", ErrInvalidData, noEOF(err))
+		}
+		if v > 1<<32-1 {
+			return Uint32Store{}, fmt.Errorf("%w: delta: value %d out of range", ErrInvalidData, v)
+		}
+		sets[key.String()] = uint32(v)
+	}
+
+	var keys []string
+	old.walk(func(key []byte, _ uint32) bool {
+		k := string(key)
+		if _, ok := sets[k]; !ok && !deletes[k] {
+			keys = append(keys, k)
+		}
+		return true
+	})
+	for k := range sets {
+		keys = append(keys, k)
+	}
+	cur := NewUint32Store(funcSource{
+		keys: keys,
+		get: func(k string) uint32 {
+			if v, ok := sets[k]; ok {
+				return v
+			}
+			v, _ := old.LookupString(k)
+			return v
+		},
+	})
+	if fp := cur.Fingerprint(); fp != curFP {
+		return Uint32Store{}, fmt.Errorf("%w: delta: result has fingerprint %#x not %#x", ErrInvalidData, fp, curFP)
+	}
+	return cur, nil
+}
+
+// noEOF converts io.EOF to io.ErrUnexpectedEOF for truncated input
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
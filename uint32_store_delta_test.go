@@ -0,0 +1,84 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestDelta(t *testing.T) {
+	m := randomSmallStrings(2048, 8)
+	ms := mapSliceN(m, len(m))
+	old := faststringmap.NewUint32Store(ms)
+
+	// change a few entries: some removed, some added and some changed value
+	changed := make(map[string]uint32, len(m))
+	for k, v := range m {
+		changed[k] = v
+	}
+	for i, k := range ms.in[:30] {
+		switch i % 3 {
+		case 0:
+			delete(changed, k)
+		case 1:
+			changed[k] = m[k]*2 + 1
+		case 2:
+			changed[k+"\x00new"] = uint32(i)
+		}
+	}
+	cur := faststringmap.NewUint32Store(mapSliceN(changed, len(changed)))
+
+	var buf bytes.Buffer
+	if err := faststringmap.WriteDelta(&buf, &old, &cur); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() > 1000 {
+		t.Errorf("delta unexpectedly large: %d bytes", buf.Len())
+	}
+	delta := buf.Bytes()
+
+	got, err := faststringmap.ApplyDelta(&old, bytes.NewReader(delta))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Fingerprint() != cur.Fingerprint() {
+		t.Error("applying delta did not give new map")
+	}
+
+	for _, n := range []int{0, 3, len(delta) - 1} {
+		if _, err := faststringmap.ApplyDelta(&old, bytes.NewReader(delta[:n])); !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("delta truncated to %d bytes: got error %v want %v", n, err, faststringmap.ErrInvalidData)
+		}
+	}
+
+	// applied to the wrong base, or to a stale copy of the right one
+	wrong := faststringmap.NewUint32StoreFromMap(map[string]uint32{"a": 1})
+	stale := old.WithKey(ms.in[0], m[ms.in[0]]+1)
+	for name, base := range map[string]*faststringmap.Uint32Store{"wrong": &wrong, "stale": &stale} {
+		if _, err := faststringmap.ApplyDelta(base, bytes.NewReader(delta)); !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("%s base: got error %v want %v", name, err, faststringmap.ErrInvalidData)
+		}
+	}
+
+	// a delta whose records do not give the map it was made for
+	changedRecord := append([]byte(nil), delta...)
+	changedRecord[len(changedRecord)-2]++ // last byte of the last record
+	if _, err := faststringmap.ApplyDelta(&old, bytes.NewReader(changedRecord)); !errors.Is(err, faststringmap.ErrInvalidData) {
+		t.Errorf("changed record: got error %v want %v", err, faststringmap.ErrInvalidData)
+	}
+
+	// a key length far larger than the delta, which must not be allocated
+	for _, n := range []uint64{1 << 40, 1<<64 - 1} {
+		bad := append([]byte(nil), delta[:21]...) // header with fingerprints
+		var buf [binary.MaxVarintLen64]byte
+		bad = append(append(bad, 1), buf[:binary.PutUvarint(buf[:], n)]...)
+		if _, err := faststringmap.ApplyDelta(&old, bytes.NewReader(append(bad, "key"...))); !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("key length %d: got error %v want %v", n, err, faststringmap.ErrInvalidData)
+		}
+	}
+}
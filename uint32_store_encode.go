@@ -0,0 +1,73 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned (wrapped) when a key required to be in the map is not present
+var ErrNotFound = errors.New("faststringmap: key not found")
+
+// Encode looks up each of keys, for example the values of a column being
+// dictionary encoded, and stores the result in the corresponding element
+// of out, which must be at least as long as keys.
+//
+// The value for a key not in the map is obtained by calling missing,
+// which may return a sentinel value or assign a new ID. If missing is nil
+// then Encode stops at the first key not in the map and returns an error
+// wrapping ErrNotFound.
+func (m *Uint32Store) Encode(keys []string, out []uint32, missing func(string) uint32) error {
+	out = out[:len(keys)]
+	for i, k := range keys {
+		v, ok := m.LookupString(k)
+		if !ok {
+			if missing == nil {
+				return fmt.Errorf("%w: %q at index %d", ErrNotFound, k, i)
+			}
+			v = missing(k)
+		}
+		out[i] = v
+	}
+	return nil
+}
+
+// LookupSortedBatch looks up each of keys and stores the results in the
+// corresponding elements of values and found, which must be at least as
+// long as keys. The trie is only followed from the end of the prefix each
+// key shares with the previous key, so keys in sorted or nearly sorted
+// order, where consecutive keys share long prefixes, visit far fewer nodes
+// than looking each key up separately. Keys in any order give correct results.
+func (m *Uint32Store) LookupSortedBatch(keys []string, values []uint32, found []bool) {
+	values, found = values[:len(keys)], found[:len(keys)]
+	if len(m.store) == 0 {
+		for i := range keys {
+			values[i], found[i] = 0, false
+		}
+		return
+	}
+	path := append(make([]uint32, 0, 64), m.root) // path[d] is store index for prev[:d]
+	prev := ""
+	for i, k := range keys {
+		d := 0
+		for d < len(path)-1 && d < len(k) && k[d] == prev[d] {
+			d++
+		}
+		path = path[:d+1]
+		idx, ok := path[d], true
+		for ; d < len(k); d++ {
+			if idx, ok = m.step(idx, k[d]); !ok {
+				break
+			}
+			path = append(path, idx)
+		}
+		if ok {
+			bv := &m.store[idx]
+			values[i], found[i] = bv.value, bv.valid
+		} else {
+			values[i], found[i] = 0, false
+		}
+		prev = k
+	}
+}
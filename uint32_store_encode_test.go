@@ -0,0 +1,82 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestEncode(t *testing.T) {
+	fm := faststringmap.NewUint32Store(mapSliceN(map[string]uint32{"a": 0, "b": 1, "c": 2}, 3))
+	keys := []string{"c", "a", "x", "b", "y", "x"}
+	out := make([]uint32, len(keys))
+
+	const sentinel = 99
+	if err := fm.Encode(keys, out, func(string) uint32 { return sentinel }); err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint32{2, 0, sentinel, 1, sentinel, sentinel}; !reflect.DeepEqual(out, want) {
+		t.Errorf("sentinel: got %v want %v", out, want)
+	}
+
+	// assign new IDs to unknown keys as they are seen
+	extra := map[string]uint32{}
+	assign := func(k string) uint32 {
+		v, ok := extra[k]
+		if !ok {
+			v = uint32(3 + len(extra))
+			extra[k] = v
+		}
+		return v
+	}
+	if err := fm.Encode(keys, out, assign); err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint32{2, 0, 3, 1, 4, 3}; !reflect.DeepEqual(out, want) {
+		t.Errorf("assign: got %v want %v", out, want)
+	}
+
+	err := fm.Encode(keys, out, nil)
+	if !errors.Is(err, faststringmap.ErrNotFound) {
+		t.Errorf("got error %v want %v", err, faststringmap.ErrNotFound)
+	}
+}
+
+func TestLookupSortedBatch(t *testing.T) {
+	m := randomSmallStrings(2000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+
+	keys := append(append([]string(nil), ms.in...), ms.out...)
+	check := func(order string) {
+		values := make([]uint32, len(keys))
+		found := make([]bool, len(keys))
+		fm.LookupSortedBatch(keys, values, found)
+		for i, k := range keys {
+			if v, ok := fm.LookupString(k); values[i] != v || found[i] != ok {
+				t.Errorf("%s %q: got %d, %v want %d, %v", order, k, values[i], found[i], v, ok)
+			}
+		}
+	}
+	check("unsorted")
+	sort.Strings(keys)
+	check("sorted")
+}
+
+func BenchmarkLookupSortedBatch(b *testing.B) {
+	m := typicalCodeStrings(nStrsBench)
+	fm := faststringmap.NewUint32Store(m)
+	keys := append([]string(nil), m.in...)
+	sort.Strings(keys)
+	values := make([]uint32, len(keys))
+	found := make([]bool, len(keys))
+	b.ResetTimer()
+	for bi := 0; bi < b.N; bi++ {
+		fm.LookupSortedBatch(keys, values, found)
+	}
+}
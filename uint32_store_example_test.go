@@ -55,7 +55,7 @@ func Example() {
 	//  {nextLo:5 nextLen:2 nextOffset:49 valid:false value:0}
 	//  {nextLo:0 nextLen:0 nextOffset:0 valid:true value:42}
 	//  {nextLo:0 nextLen:0 nextOffset:0 valid:true value:27644437}
-	// ]}
+	// ] n:3}
 }
 
 type exampleSource map[string]uint32
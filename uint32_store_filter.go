@@ -0,0 +1,111 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// Filter returns a map with the keys of m for which keep returns true,
+// with the same values. m is not modified. The new trie is built while
+// walking m, without collecting and sorting the keys as NewUint32Store
+// would, and the next nodes of branches left with no keys are dropped.
+func (m *Uint32Store) Filter(keep func(string, uint32) bool) Uint32Store {
+	if len(m.store) == 0 {
+		return Uint32Store{store: []byteValue{{}}}
+	}
+	f := filterer{
+		from: m.store,
+		keep: keep,
+		to:   make([]byteValue, 1, len(m.store)),
+	}
+	f.filter(m.root)
+	to := make([]byteValue, len(f.to))
+	copy(to, f.to)
+	return Uint32Store{store: to, n: f.n}
+}
+
+// filterer is used only during Filter
+type (
+	filterer struct {
+		from []byteValue
+		keep func(string, uint32) bool
+		to   []byteValue
+		n    int // number of keys in to
+	}
+
+	// filterFrame is a node being visited by filterer.filter
+	filterFrame struct {
+		at, i    uint32 // index in to and from
+		next     int    // next node to visit from from[i]
+		nextLo   uint32 // index in to of the next nodes
+		followed bool   // whether any next node visited leads to keys
+	}
+)
+
+// filter sets to[0] to the filtered from[i], the root, and adds the next
+// nodes, using an explicit stack like walkNodesFrom
+func (f *filterer) filter(i uint32) {
+	key := make([]byte, 0, 256)
+	var frames [walkStackSize]filterFrame
+	stack := append(frames[:0], f.enter(0, i, key))
+	for {
+		fr := &stack[len(stack)-1]
+		bv := &f.from[fr.i]
+		if fr.next < int(bv.nextLen) {
+			j := uint32(fr.next)
+			fr.next++
+			key = append(key, bv.nextOffset+byte(j))
+			stack = append(stack, f.enter(fr.nextLo+j, bv.nextLo+j, key))
+			continue
+		}
+		followed := f.leave(fr, bv)
+		if stack = stack[:len(stack)-1]; len(stack) == 0 {
+			return
+		}
+		key = key[:len(key)-1]
+		if followed {
+			stack[len(stack)-1].followed = true
+		}
+	}
+}
+
+// enter sets to[at] to the filtered from[i], where key is the byte
+// sequence leading to it, and adds space for its next nodes
+func (f *filterer) enter(at, i uint32, key []byte) filterFrame {
+	bv := &f.from[i]
+	if bv.valid && f.keep(string(key), bv.value) {
+		f.to[at].valid, f.to[at].value = true, bv.value
+		f.n++
+	}
+	nextLo := uint32(len(f.to))
+	f.to = append(f.to, make([]byteValue, bv.nextLen)...)
+	return filterFrame{at: at, i: i, nextLo: nextLo}
+}
+
+// leave sets the range of next nodes of to[fr.at] once they have all been
+// visited, dropping them if none lead to keys, and returns whether to[fr.at]
+// leads to any keys
+func (f *filterer) leave(fr *filterFrame, bv *byteValue) bool {
+	to := &f.to[fr.at]
+	if !fr.followed {
+		// drop the next nodes, which are the last ones added
+		f.to = f.to[:fr.nextLo]
+		return to.valid
+	}
+	to.nextLo, to.nextLen, to.nextOffset = fr.nextLo, bv.nextLen, bv.nextOffset
+	return true
+}
+
+// TransformValues returns a map with the same keys as m and the value for
+// each key given by f applied to the key and its value in m, which is not
+// modified. The store is copied with its shape unchanged, so this is much
+// cheaper than building a new map.
+func (m *Uint32Store) TransformValues(f func(string, uint32) uint32) Uint32Store {
+	if len(m.store) == 0 {
+		return Uint32Store{store: []byteValue{{}}}
+	}
+	t := Uint32Store{store: make([]byteValue, len(m.store)), n: m.n, root: m.root}
+	copy(t.store, m.store)
+	t.walkRefFrom(t.root, make([]byte, 0, 256), func(k string, v *uint32) bool {
+		*v = f(k, *v)
+		return true
+	})
+	return t
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestFilter(t *testing.T) {
+	m := randomSmallStrings(3000, 6)
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	keep := func(k string, v uint32) bool { return v%3 == 0 || strings.HasPrefix(k, "a") }
+	filtered := fm.Filter(keep)
+
+	ms := mapSlice{m: m}
+	for k, v := range m {
+		if keep(k, v) {
+			ms.in = append(ms.in, k)
+		} else {
+			ms.out = append(ms.out, k)
+		}
+	}
+	checkLookuper(t, "filtered", &filtered, ms)
+	checkLookuper(t, "original", &fm, mapSliceN(m, len(m)))
+
+	none := fm.Filter(func(string, uint32) bool { return false })
+	if st := none.MemStats(); st.Keys != 0 || st.Nodes != 1 {
+		t.Errorf("filter none got %d keys %d nodes", st.Keys, st.Nodes)
+	}
+	var zero faststringmap.Uint32Store
+	none = zero.Filter(func(string, uint32) bool { return true })
+	checkLookuper(t, "zero", &none, mapSlice{out: []string{"", "a"}})
+}
+
+func TestTransformValues(t *testing.T) {
+	m := randomSmallStrings(3000, 6)
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	double := func(k string, v uint32) uint32 { return v*2 + uint32(len(k)) }
+	tm := fm.TransformValues(double)
+
+	want := make(map[string]uint32, len(m))
+	for k, v := range m {
+		want[k] = double(k, v)
+	}
+	checkLookuper(t, "transformed", &tm, mapSliceN(want, len(want)))
+	checkLookuper(t, "original", &fm, mapSliceN(m, len(m)))
+	if tm.SizeInBytes() != fm.SizeInBytes() {
+		t.Errorf("size got %d want %d", tm.SizeInBytes(), fm.SizeInBytes())
+	}
+
+	var zero faststringmap.Uint32Store
+	tm = zero.TransformValues(double)
+	checkLookuper(t, "zero", &tm, mapSlice{out: []string{"", "a"}})
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+)
+
+// Fingerprint returns a 64-bit xxHash (XXH64) over the keys and values in
+// the map in sorted key order. Two maps with the same contents have the
+// same fingerprint regardless of how they were built, so it can be used
+// to compare maps or key caches without a deep comparison.
+func (m *Uint32Store) Fingerprint() uint64 {
+	h := newXXHash64()
+	m.writeContents(h)
+	return h.Sum64()
+}
+
+// Hash returns a 128-bit FNV-1a hash over the keys and values in the map
+// in sorted key order, like Fingerprint but with a much smaller chance of
+// two different maps having the same hash. It is stable across versions
+// and platforms, so it can be stored to check that a persisted map holds
+// the expected version of a data set.
+func (m *Uint32Store) Hash() [16]byte {
+	h := fnv.New128a()
+	m.writeContents(h)
+	var sum [16]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// writeContents writes the keys and values in the map to h in sorted key order
+func (m *Uint32Store) writeContents(h hash.Hash) {
+	var buf [binary.MaxVarintLen64 + 4]byte
+	m.walk(func(key []byte, v uint32) bool {
+		// length prefix each key so that key boundaries are unambiguous
+		n := binary.PutUvarint(buf[:], uint64(len(key)))
+		h.Write(buf[:n])
+		h.Write(key)
+		binary.LittleEndian.PutUint32(buf[:4], v)
+		h.Write(buf[:4])
+		return true
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestFingerprint(t *testing.T) {
+	m := randomSmallStrings(1024, 8)
+	ms := mapSliceN(m, len(m))
+	fm := faststringmap.NewUint32Store(ms)
+	fp := fm.Fingerprint()
+
+	// reversing the key order supplied must not change the fingerprint
+	rev := mapSlice{m: m, in: make([]string, 0, len(ms.in))}
+	for i := len(ms.in) - 1; i >= 0; i-- {
+		rev.in = append(rev.in, ms.in[i])
+	}
+	fm = faststringmap.NewUint32Store(rev)
+	if got := fm.Fingerprint(); got != fp {
+		t.Errorf("fingerprint depends on key order: got %x want %x", got, fp)
+	}
+
+	// changing a single value must change the fingerprint
+	changed := make(map[string]uint32, len(m))
+	for k, v := range m {
+		changed[k] = v
+	}
+	changed[ms.in[0]]++
+	fm = faststringmap.NewUint32Store(mapSliceN(changed, len(changed)))
+	if got := fm.Fingerprint(); got == fp {
+		t.Errorf("fingerprint unchanged after changing value of %q", ms.in[0])
+	}
+}
+
+func TestFingerprintKeyBoundaries(t *testing.T) {
+	a := faststringmap.NewUint32Store(mapSliceN(map[string]uint32{"ab": 1, "c": 2}, 2))
+	b := faststringmap.NewUint32Store(mapSliceN(map[string]uint32{"a": 1, "bc": 2}, 2))
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Error("fingerprints equal for maps with different keys")
+	}
+	e1 := faststringmap.NewUint32Store(mapSliceN(map[string]uint32{}, 0))
+	e2 := faststringmap.NewUint32Store(mapSliceN(map[string]uint32{"x": 1}, 0))
+	if e1.Fingerprint() != e2.Fingerprint() {
+		t.Error("fingerprints differ for empty maps")
+	}
+
+	// XXH64 of no bytes, and of the length of "a", "a" and the value 1
+	if fp := e1.Fingerprint(); fp != 0xef46db3751d8e999 {
+		t.Errorf("empty map fingerprint got %#x want %#x", fp, uint64(0xef46db3751d8e999))
+	}
+	a1 := faststringmap.NewUint32Store(mapSliceN(map[string]uint32{"a": 1}, 1))
+	if fp := a1.Fingerprint(); fp != 0x6e652d3a8142f40b {
+		t.Errorf("fingerprint got %#x want %#x", fp, uint64(0x6e652d3a8142f40b))
+	}
+}
+
+func TestHash(t *testing.T) {
+	m := map[string]uint32{"a": 1, "ab": 2, "b": 3}
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	h := fm.Hash()
+	updated := faststringmap.NewUint32StoreFromMap(map[string]uint32{"a": 1, "b": 3})
+	updated = updated.WithKey("ab", 2)
+	if got := updated.Hash(); got != h {
+		t.Errorf("hash depends on how the map was built: got %x want %x", got, h)
+	}
+	if changed := fm.WithKey("ab", 4); changed.Hash() == h {
+		t.Error("hash unchanged after changing a value")
+	}
+
+	// the hash of an empty map is the FNV-1a offset basis, and must not
+	// change between versions
+	var empty faststringmap.Uint32Store
+	if got, want := fmt.Sprintf("%x", empty.Hash()), "6c62272e07bb014262b821756295c58d"; got != want {
+		t.Errorf("empty hash got %s want %s", got, want)
+	}
+}
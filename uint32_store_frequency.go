@@ -0,0 +1,33 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+// NewUint32StoreByFrequency creates a map from each of keys to an ID where
+// keys with a higher weight are assigned smaller IDs, which suits variable
+// length or entropy coding of the IDs. Keys of equal weight are ordered by
+// key so the result does not depend on the order of keys. It also returns
+// the keys indexed by ID. keys must not contain duplicates and is not modified.
+func NewUint32StoreByFrequency(keys []string, weight func(string) uint64) (Uint32Store, []string) {
+	byID := append([]string(nil), keys...)
+	weights := make(map[string]uint64, len(byID))
+	for _, k := range byID {
+		weights[k] = weight(k)
+	}
+	sort.Slice(byID, func(i, j int) bool {
+		wi, wj := weights[byID[i]], weights[byID[j]]
+		if wi != wj {
+			return wi > wj
+		}
+		return byID[i] < byID[j]
+	})
+	ids := make(map[string]uint32, len(byID))
+	for i, k := range byID {
+		ids[k] = uint32(i)
+	}
+	return NewUint32Store(funcSource{
+		keys: byID,
+		get:  func(k string) uint32 { return ids[k] },
+	}), byID
+}
@@ -0,0 +1,28 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNewUint32StoreByFrequency(t *testing.T) {
+	counts := map[string]uint64{"the": 100, "a": 80, "cat": 3, "dog": 3, "zebra": 1}
+	keys := []string{"zebra", "dog", "cat", "a", "the"}
+	fm, byID := faststringmap.NewUint32StoreByFrequency(keys, func(k string) uint64 { return counts[k] })
+
+	if want := []string{"the", "a", "cat", "dog", "zebra"}; !reflect.DeepEqual(byID, want) {
+		t.Errorf("got %q want %q", byID, want)
+	}
+	for id, k := range byID {
+		if v, ok := fm.LookupString(k); !ok || v != uint32(id) {
+			t.Errorf("%q: got %d, %v want %d, true", k, v, ok, id)
+		}
+	}
+	if keys[0] != "zebra" {
+		t.Error("keys modified")
+	}
+}
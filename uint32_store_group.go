@@ -0,0 +1,78 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// GroupByPrefix returns the number of keys in the map for each distinct
+// prefix of length n. Keys shorter than n bytes are counted under the
+// whole key.
+func (m *Uint32Store) GroupByPrefix(n int) map[string]int {
+	counts := make(map[string]int)
+	if len(m.store) == 0 {
+		return counts
+	}
+	m.groupFrom(m.root, make([]byte, 0, n), counts, func(key []byte) bool { return len(key) == n })
+	return counts
+}
+
+// GroupByDelimiter returns the number of keys in the map for each distinct
+// prefix up to and including the first occurrence of sep. Keys which do
+// not contain sep are counted under the whole key.
+func (m *Uint32Store) GroupByDelimiter(sep byte) map[string]int {
+	counts := make(map[string]int)
+	if len(m.store) == 0 {
+		return counts
+	}
+	m.groupFrom(m.root, nil, counts, func(key []byte) bool { return len(key) > 0 && key[len(key)-1] == sep })
+	return counts
+}
+
+// groupFrom descends from store index i, reached by key, until isGroup
+// reports that key is a complete group prefix and then counts the keys
+// under it, using an explicit stack like walkNodesFrom
+func (m *Uint32Store) groupFrom(i uint32, key []byte, counts map[string]int, isGroup func([]byte) bool) {
+	var frames [walkStackSize]iterFrame
+	stack := append(frames[:0], iterFrame{node: i, next: -1})
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		bv := &m.store[f.node]
+		switch {
+		case f.next < 0 && isGroup(key):
+			if n := m.countFrom(f.node); n > 0 {
+				counts[string(key)] += n
+			}
+			f.next = int(bv.nextLen) // the next nodes have been counted
+		case f.next < 0:
+			f.next = 0
+			if bv.valid {
+				counts[string(key)]++
+			}
+		case f.next < int(bv.nextLen):
+			j := f.next
+			f.next++
+			stack = append(stack, iterFrame{node: bv.nextLo + uint32(j), next: -1})
+			key = append(key, bv.nextOffset+byte(j))
+		default:
+			if stack = stack[:len(stack)-1]; len(stack) > 0 {
+				key = key[:len(key)-1]
+			}
+		}
+	}
+}
+
+// countFrom returns the number of keys in the sub-trie rooted at store index i
+func (m *Uint32Store) countFrom(i uint32) int {
+	var frames [walkStackSize]uint32
+	stack := append(frames[:0], i) // nodes still to count, in any order
+	n := 0
+	for len(stack) > 0 {
+		bv := &m.store[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if bv.valid {
+			n++
+		}
+		for j := uint32(0); j < uint32(bv.nextLen); j++ {
+			stack = append(stack, bv.nextLo+j)
+		}
+	}
+	return n
+}
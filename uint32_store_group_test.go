@@ -0,0 +1,40 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestGroupBy(t *testing.T) {
+	m := map[string]uint32{
+		"fruit/apple":  1,
+		"fruit/pear":   2,
+		"veg/leek":     3,
+		"veg/":         4,
+		"veg":          5,
+		"misc":         6,
+		"fruitcake/x":  7,
+		"veg/kale/big": 8,
+	}
+	fm := faststringmap.NewUint32Store(mapSliceN(m, len(m)))
+
+	if got, want := fm.GroupByDelimiter('/'), map[string]int{
+		"fruit/": 2, "veg/": 3, "veg": 1, "misc": 1, "fruitcake/": 1,
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByDelimiter got %v want %v", got, want)
+	}
+	if got, want := fm.GroupByPrefix(3), map[string]int{
+		"fru": 3, "veg": 4, "mis": 1,
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByPrefix(3) got %v want %v", got, want)
+	}
+	if got, want := fm.GroupByPrefix(4), map[string]int{
+		"frui": 3, "veg/": 3, "veg": 1, "misc": 1,
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByPrefix(4) got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NewUint32StoreIndexed creates a map from each of keys to its index in
+// keys, so that the caller can keep the values for the keys in a slice
+// of any type in the same order and look them up by the index. This keeps
+// the map small and free of pointers however large the values are.
+// keys is not modified. It returns an error wrapping ErrDuplicateKey if a
+// key occurs more than once.
+func NewUint32StoreIndexed(keys []string) (Uint32Store, error) {
+	order := make([]uint32, len(keys))
+	for i := range order {
+		order[i] = uint32(i)
+	}
+	sort.Slice(order, func(i, j int) bool { return keys[order[i]] < keys[order[j]] })
+	sorted := make([]string, len(keys))
+	for i, ki := range order {
+		sorted[i] = keys[ki]
+		if i > 0 && sorted[i] == sorted[i-1] {
+			return Uint32Store{}, fmt.Errorf("%w: %q", ErrDuplicateKey, sorted[i])
+		}
+	}
+	return newUint32StoreSorted(sorted, func(i int) uint32 { return order[i] }), nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNewUint32StoreIndexed(t *testing.T) {
+	type entry struct {
+		name  string
+		score float64
+	}
+	keys := []string{"pear", "apple", "", "banana", "apples"}
+	values := []entry{{"Pear", 1.5}, {"Apple", 2}, {"None", 0}, {"Banana", 3}, {"Apples", 4}}
+	m, err := faststringmap.NewUint32StoreIndexed(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != len(keys) {
+		t.Errorf("Len got %d want %d", m.Len(), len(keys))
+	}
+	for i, k := range keys {
+		if v, ok := m.LookupString(k); !ok || v != uint32(i) {
+			t.Errorf("%q: got %d, %v want %d, true", k, v, ok, i)
+		} else if values[v] != values[i] {
+			t.Errorf("%q: got %v want %v", k, values[v], values[i])
+		}
+	}
+	if v, ok := m.LookupString("app"); ok {
+		t.Errorf("app present when not expected, got %d", v)
+	}
+
+	if _, err := faststringmap.NewUint32StoreIndexed([]string{"a", "b", "a"}); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("duplicate: got %v want ErrDuplicateKey", err)
+	}
+	if m, err := faststringmap.NewUint32StoreIndexed(nil); err != nil || m.Len() != 0 {
+		t.Errorf("empty: got Len %d, %v", m.Len(), err)
+	}
+}
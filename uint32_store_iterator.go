@@ -0,0 +1,118 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+type (
+	// Uint32StoreIterator steps through the keys of a Uint32Store in sorted
+	// order, starting from the first key or from the position given to
+	// Seek, so that a large map can be scanned in resumable chunks:
+	//
+	//	it := m.Iterator()
+	//	it.Seek(from)
+	//	for it.Next() {
+	//		use(it.Key(), it.Value())
+	//	}
+	Uint32StoreIterator struct {
+		m     *Uint32Store
+		stack []iterFrame // nodes from the root to the current node
+		key   []byte      // byte sequence leading to the top of stack
+		value uint32
+	}
+
+	// iterFrame is a node being visited by Uint32StoreIterator
+	iterFrame struct {
+		node uint32
+		next int // next node to visit from node, or -1 for node itself
+	}
+)
+
+// Iterator returns an iterator positioned before the first key of m
+func (m *Uint32Store) Iterator() *Uint32StoreIterator {
+	it := &Uint32StoreIterator{m: m, key: make([]byte, 0, 64)}
+	it.Seek("")
+	return it
+}
+
+// Seek positions the iterator so that Next moves to the first key in the
+// map which is greater than or equal to k
+func (it *Uint32StoreIterator) Seek(k string) {
+	it.stack, it.key = it.stack[:0], it.key[:0]
+	store := it.m.store
+	if len(store) == 0 {
+		return
+	}
+	it.stack = append(it.stack, iterFrame{node: it.m.root, next: -1})
+	for d := 0; d < len(k); d++ {
+		f := &it.stack[d]
+		bv := &store[f.node]
+		// the key for the node is before k
+		c := k[d]
+		if c < bv.nextOffset {
+			f.next = 0 // every next node is after k
+			return
+		}
+		j := int(c - bv.nextOffset)
+		if j >= int(bv.nextLen) {
+			f.next = int(bv.nextLen) // every next node is before k
+			return
+		}
+		f.next = j + 1
+		it.stack = append(it.stack, iterFrame{node: bv.nextLo + uint32(j), next: -1})
+		it.key = append(it.key, c)
+	}
+}
+
+// Next moves to the next key, returning false when there are no more
+func (it *Uint32StoreIterator) Next() bool {
+	store := it.m.store
+	for len(it.stack) > 0 {
+		f := &it.stack[len(it.stack)-1]
+		bv := &store[f.node]
+		switch {
+		case f.next < 0:
+			f.next = 0
+			if bv.valid {
+				it.value = bv.value
+				return true
+			}
+		case f.next < int(bv.nextLen):
+			j := f.next
+			f.next++
+			it.stack = append(it.stack, iterFrame{node: bv.nextLo + uint32(j), next: -1})
+			it.key = append(it.key, bv.nextOffset+byte(j))
+		default:
+			it.stack = it.stack[:len(it.stack)-1]
+			if len(it.key) > 0 {
+				it.key = it.key[:len(it.key)-1]
+			}
+		}
+	}
+	return false
+}
+
+// Key returns the current key
+func (it *Uint32StoreIterator) Key() string { return string(it.key) }
+
+// KeyBytes returns the current key, which is only valid until the next
+// call to Next or Seek
+func (it *Uint32StoreIterator) KeyBytes() []byte { return it.key }
+
+// Value returns the value for the current key
+func (it *Uint32StoreIterator) Value() uint32 { return it.value }
+
+// KeysAfter returns up to limit keys in the map greater than after in
+// sorted order, for keyset pagination where each page starts after the
+// last key of the previous one
+func (m *Uint32Store) KeysAfter(after string, limit int) []string {
+	if limit <= 0 {
+		return nil
+	}
+	it := m.Iterator()
+	// the first key greater than after is the first at or after after+"\x00"
+	it.Seek(after + "\x00")
+	var keys []string
+	for len(keys) < limit && it.Next() {
+		keys = append(keys, it.Key())
+	}
+	return keys
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestUint32StoreIterator(t *testing.T) {
+	m := randomSmallStrings(2000, 5)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	sorted := append([]string(nil), ms.in...)
+	sort.Strings(sorted)
+
+	collect := func(it *faststringmap.Uint32StoreIterator) []string {
+		var keys []string
+		for it.Next() {
+			if it.Value() != m[it.Key()] || string(it.KeyBytes()) != it.Key() {
+				t.Errorf("%q: got %d want %d", it.Key(), it.Value(), m[it.Key()])
+			}
+			keys = append(keys, it.Key())
+		}
+		return keys
+	}
+	it := fm.Iterator()
+	if got := collect(it); !equalStrings(got, sorted) {
+		t.Errorf("all: got %d keys want %d in sorted order", len(got), len(sorted))
+	}
+	for _, s := range append(append([]string{"", "\x00", "\xff", "a", "b~~~~~~"}, ms.out[:200]...), ms.in[:200]...) {
+		it.Seek(s)
+		want := sorted[sort.SearchStrings(sorted, s):]
+		if got := collect(it); !equalStrings(got, want) {
+			t.Errorf("Seek %q: got %d keys want %d", s, len(got), len(want))
+		}
+	}
+
+	var zero faststringmap.Uint32Store
+	it = zero.Iterator()
+	it.Seek("a")
+	if it.Next() {
+		t.Errorf("zero map has key %q", it.Key())
+	}
+}
+
+func TestKeysAfter(t *testing.T) {
+	m := randomSmallStrings(1000, 5)
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	sorted := make([]string, 0, len(m))
+	for k := range m {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	// paging through gives every key but the first, which is ""
+	var all []string
+	for after := ""; ; {
+		keys := fm.KeysAfter(after, 77)
+		if len(keys) == 0 {
+			break
+		}
+		all = append(all, keys...)
+		after = keys[len(keys)-1]
+	}
+	if !equalStrings(all, sorted[1:]) {
+		t.Errorf("pages got %d keys want %d", len(all), len(sorted)-1)
+	}
+	if got := fm.KeysAfter(sorted[5], 2); !equalStrings(got, sorted[6:8]) {
+		t.Errorf("after %q got %q want %q", sorted[5], got, sorted[6:8])
+	}
+	if got := fm.KeysAfter("", 0); got != nil {
+		t.Errorf("limit 0 got %q", got)
+	}
+}
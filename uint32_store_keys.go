@@ -0,0 +1,197 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Front coded key stream format: keysMagic, a flags byte, the uvarint
+// number of keys and then the keys in sorted order in blocks of up to
+// keysPerBlock keys. Each block is the uvarint number of keys in it, the
+// uvarint length of its body and the body, which is compressed with
+// DEFLATE on its own if flag keysCompressed is set, so that a block can be
+// decoded without the ones before it. The body holds each key as the
+// uvarint length of the prefix shared with the previous key in the block,
+// the uvarint length of the rest of the key and the rest of the key.
+const (
+	keysMagic      = "FSMK\x02"
+	keysCompressed = 1
+	keysPerBlock   = 1024
+)
+
+// WriteKeys writes the keys of the map, without values, to w in sorted
+// order using front coding, which stores each key as the length of the
+// prefix shared with the previous key plus the remaining bytes. The keys
+// are written in blocks, and if compress is true each block is also
+// compressed with DEFLATE independently of the others. The position of a
+// key in the stream can serve as its ID.
+func (m *Uint32Store) WriteKeys(w io.Writer, compress bool) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(keysMagic)
+	var flags byte
+	if compress {
+		flags |= keysCompressed
+	}
+	bw.WriteByte(flags)
+	var buf [binary.MaxVarintLen64]byte
+	bw.Write(buf[:binary.PutUvarint(buf[:], uint64(m.Len()))])
+
+	var block, compressed bytes.Buffer
+	var fw *flate.Writer
+	if compress {
+		fw, _ = flate.NewWriter(&compressed, flate.DefaultCompression) // only errors for invalid level
+	}
+	var err error
+	inBlock := 0
+	writeBlock := func() {
+		body := block.Bytes()
+		if fw != nil {
+			compressed.Reset()
+			fw.Reset(&compressed)
+			fw.Write(body) // writes to a bytes.Buffer do not fail
+			fw.Close()
+			body = compressed.Bytes()
+		}
+		bw.Write(buf[:binary.PutUvarint(buf[:], uint64(inBlock))])
+		bw.Write(buf[:binary.PutUvarint(buf[:], uint64(len(body)))])
+		_, err = bw.Write(body)
+		block.Reset()
+		inBlock = 0
+	}
+	prev := make([]byte, 0, 256)
+	m.walk(func(key []byte, _ uint32) bool {
+		shared := 0
+		for inBlock > 0 && shared < len(prev) && shared < len(key) && prev[shared] == key[shared] {
+			shared++
+		}
+		block.Write(buf[:binary.PutUvarint(buf[:], uint64(shared))])
+		block.Write(buf[:binary.PutUvarint(buf[:], uint64(len(key)-shared))])
+		block.Write(key[shared:])
+		prev = append(prev[:0], key...)
+		if inBlock++; inBlock == keysPerBlock {
+			writeBlock()
+		}
+		return err == nil
+	})
+	if err == nil && inBlock > 0 {
+		writeBlock()
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ReadKeys reads keys written by WriteKeys from r, returning them in sorted order
+func ReadKeys(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+	hdr := make([]byte, len(keysMagic)+1)
+	if _, err := io.ReadFull(br, hdr); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidData, noEOF(err))
+	}
+	if string(hdr[:len(keysMagic)]) != keysMagic {
+		return nil, fmt.Errorf("%w: bad key stream header %q", ErrInvalidData, hdr)
+	}
+	compressed := hdr[len(keysMagic)]&keysCompressed != 0
+
+	readUvarint := func(r io.ByteReader) (uint64, error) {
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidData, noEOF(err))
+		}
+		return n, nil
+	}
+	n, err := readUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, minUint64(n, 1<<16))
+	var block bytes.Buffer
+	var fr io.ReadCloser
+	for uint64(len(keys)) < n {
+		inBlock, err := readUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		if inBlock == 0 || inBlock > n-uint64(len(keys)) {
+			return nil, fmt.Errorf("%w: block of %d keys with %d keys left", ErrInvalidData, inBlock, n-uint64(len(keys)))
+		}
+		size, err := readUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		// copy rather than allocate size bytes, which may be corrupt
+		block.Reset()
+		if _, err := io.CopyN(&block, br, int64(minUint64(size, 1<<62))); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, noEOF(err))
+		}
+		var body *bufio.Reader
+		if compressed {
+			if fr == nil {
+				fr = flate.NewReader(&block)
+			} else {
+				fr.(flate.Resetter).Reset(&block, nil)
+			}
+			body = bufio.NewReader(fr)
+		} else {
+			body = bufio.NewReader(&block)
+		}
+		if keys, err = readKeysBlock(body, keys, inBlock); err != nil {
+			return nil, err
+		}
+		// check the block is complete with nothing left over
+		if _, err := body.ReadByte(); err != io.EOF {
+			if err == nil {
+				err = fmt.Errorf("data after the last key of a block")
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+	}
+	return keys, nil
+}
+
+// readKeysBlock reads a block of n keys written by WriteKeys from body and
+// appends them to keys, checking they follow on in sorted order
+func readKeysBlock(body *bufio.Reader, keys []string, n uint64) ([]string, error) {
+	var prev []byte
+	for i := uint64(0); i < n; i++ {
+		shared, err := binary.ReadUvarint(body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, noEOF(err))
+		}
+		rest, err := binary.ReadUvarint(body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidData, noEOF(err))
+		}
+		if shared > uint64(len(prev)) {
+			return nil, fmt.Errorf("%w: key %d shares %d bytes with previous key of %d bytes", ErrInvalidData, len(keys), shared, len(prev))
+		}
+		key := prev[:shared]
+		for j := uint64(0); j < rest; j++ {
+			b, err := body.ReadByte()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidData, noEOF(err))
+			}
+			key = append(key, b)
+		}
+		if len(keys) > 0 && string(key) <= keys[len(keys)-1] {
+			return nil, fmt.Errorf("%w: key %d not in sorted order", ErrInvalidData, len(keys))
+		}
+		keys = append(keys, string(key))
+		prev = key
+	}
+	return keys, nil
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
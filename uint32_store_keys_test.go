@@ -0,0 +1,49 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestWriteReadKeys(t *testing.T) {
+	fm := faststringmap.NewUint32Store(typicalCodeStrings(5000))
+	want := fm.AppendSortedKeys(nil)
+	sizes := map[bool]int{}
+	for _, compress := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := fm.WriteKeys(&buf, compress); err != nil {
+			t.Fatal(err)
+		}
+		sizes[compress] = buf.Len()
+		b := buf.Bytes()
+		got, err := faststringmap.ReadKeys(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalStrings(got, want) {
+			t.Errorf("compress %v: read %d keys want %d", compress, len(got), len(want))
+		}
+		for _, n := range []int{0, 5, len(b) - 1} {
+			if _, err := faststringmap.ReadKeys(bytes.NewReader(b[:n])); !errors.Is(err, faststringmap.ErrInvalidData) {
+				t.Errorf("compress %v truncated to %d: got error %v want %v", compress, n, err, faststringmap.ErrInvalidData)
+			}
+		}
+	}
+	if sizes[true] >= sizes[false] {
+		t.Errorf("compressed size %d not less than uncompressed %d", sizes[true], sizes[false])
+	}
+
+	var empty faststringmap.Uint32Store
+	var buf bytes.Buffer
+	if err := empty.WriteKeys(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := faststringmap.ReadKeys(&buf); err != nil || len(got) != 0 {
+		t.Errorf("empty: got %q, %v", got, err)
+	}
+}
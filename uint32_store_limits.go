@@ -0,0 +1,206 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+	"unsafe"
+)
+
+// ErrTooLarge is returned (wrapped) when a map would exceed the limits it
+// is built with
+var ErrTooLarge = errors.New("faststringmap: map too large")
+
+// BuildLimits restricts the maps which can be built from key sets which
+// may be adversarial or unexpectedly large. A zero field means no limit,
+// although a map can never have more nodes than can be indexed by a uint32.
+type BuildLimits struct {
+	MaxNodes    int // maximum number of nodes in the map
+	MaxMemBytes int // maximum bytes of memory held by the map
+	MaxKeyLen   int // maximum length in bytes of a key
+}
+
+// NewUint32StoreLimits creates from the data supplied in src like
+// NewUint32Store but fails with an error wrapping ErrTooLarge as soon as
+// the map is found to exceed the limits in lim, rather than building a
+// huge map or one with more nodes than can be indexed
+func NewUint32StoreLimits(src Uint32Source, lim BuildLimits) (m Uint32Store, err error) {
+	keys := src.AppendKeys([]string(nil))
+	if lim.MaxKeyLen > 0 {
+		for _, k := range keys {
+			if len(k) > lim.MaxKeyLen {
+				return Uint32Store{}, fmt.Errorf("%w: key of %d bytes exceeds limit of %d", ErrTooLarge, len(k), lim.MaxKeyLen)
+			}
+		}
+	}
+	// each key needs a node of its own, and even an empty map has a root
+	maxLen := lim.maxNodes()
+	if uint64(len(keys)) > maxLen || maxLen == 0 {
+		return Uint32Store{}, fmt.Errorf("%w: %d keys exceed limit of %d nodes", ErrTooLarge, len(keys), maxLen)
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		return newUint32StoreSorted(keys, nil), nil
+	}
+	defer recoverBuildAbort(&m, &err)
+	b := uint32Builder{
+		keys:   keys,
+		value:  func(i int) uint32 { return src.Get(keys[i]) },
+		maxLen: maxLen,
+	}
+	return Uint32Store{store: b.build(), n: len(keys)}, nil
+}
+
+// maxNodes returns the most nodes allowed by lim and by the uint32 indexes
+func (lim *BuildLimits) maxNodes() uint64 {
+	n := uint64(math.MaxUint32) + 1
+	if lim.MaxNodes > 0 && uint64(lim.MaxNodes) < n {
+		n = uint64(lim.MaxNodes)
+	}
+	if lim.MaxMemBytes > 0 {
+		if m := uint64(lim.MaxMemBytes) / uint64(unsafe.Sizeof(byteValue{})); m < n {
+			n = m
+		}
+	}
+	return n
+}
+
+// LoadLimits restricts the maps which can be loaded from persisted data
+// which may be malformed or malicious. A zero field means no limit.
+type LoadLimits struct {
+	MaxNodes int // maximum number of nodes in the map
+	MaxDepth int // maximum length in bytes of the keys
+}
+
+// UnmarshalBinaryLimits is like UnmarshalBinary but also fails if the map
+// exceeds the limits in lim. As with UnmarshalBinary the error wraps
+// ErrInvalidData if the data is malformed and the map is unchanged.
+func (m *Uint32Store) UnmarshalBinaryLimits(data []byte, lim LoadLimits) error {
+	h, err := parseHeader(data)
+	if err != nil {
+		return err
+	}
+	if err := lim.checkNodes(h.nodes); err != nil {
+		return err
+	}
+	data = data[persistHeaderSize:]
+	if uint64(len(data)) != uint64(h.nodes)*persistNodeSize {
+		return fmt.Errorf("%w: %d bytes of node data want %d for %d nodes", ErrInvalidData, len(data), uint64(h.nodes)*persistNodeSize, h.nodes)
+	}
+	if crc := crc32.Checksum(data, crcTable); crc != h.checksum {
+		return fmt.Errorf("%w: checksum %08x want %08x", ErrInvalidData, crc, h.checksum)
+	}
+	store := make([]byteValue, h.nodes)
+	for i := range store {
+		b := data[i*persistNodeSize:]
+		if err := checkNode(b, uint32(i), h.nodes); err != nil {
+			return err
+		}
+		store[i] = getNode(b)
+	}
+	n, err := lim.checkShape(store)
+	if err != nil {
+		return err
+	}
+	*m = Uint32Store{store: store, n: n}
+	return nil
+}
+
+// ReadUint32Store reads a map written by Uint32Store.WriteTo from r
+// subject to the limits in lim. Memory is only allocated as node data is
+// read so a header claiming a huge map does not cause a huge allocation.
+// Nothing is read from r after the map.
+func ReadUint32Store(r io.Reader, lim LoadLimits) (Uint32Store, error) {
+	hdr := make([]byte, persistHeaderSize)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return Uint32Store{}, noEOF(err)
+	}
+	h, err := parseHeader(hdr)
+	if err != nil {
+		return Uint32Store{}, err
+	}
+	if err := lim.checkNodes(h.nodes); err != nil {
+		return Uint32Store{}, err
+	}
+
+	var store []byteValue
+	crc := uint32(0)
+	buf := make([]byte, verifyChunkNodes*persistNodeSize)
+	for lo := uint32(0); lo < h.nodes; lo += verifyChunkNodes {
+		n := h.nodes - lo
+		if n > verifyChunkNodes {
+			n = verifyChunkNodes
+		}
+		chunk := buf[:n*persistNodeSize]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return Uint32Store{}, noEOF(err)
+		}
+		crc = crc32.Update(crc, crcTable, chunk)
+		for i := uint32(0); i < n; i++ {
+			b := chunk[i*persistNodeSize:]
+			if err := checkNode(b, lo+i, h.nodes); err != nil {
+				return Uint32Store{}, err
+			}
+			store = append(store, getNode(b))
+		}
+	}
+	if crc != h.checksum {
+		return Uint32Store{}, fmt.Errorf("%w: checksum %08x want %08x", ErrInvalidData, crc, h.checksum)
+	}
+	n, err := lim.checkShape(store)
+	if err != nil {
+		return Uint32Store{}, err
+	}
+	return Uint32Store{store: store[:len(store):len(store)], n: n}, nil
+}
+
+// checkNodes checks the number of nodes against the limit
+func (lim *LoadLimits) checkNodes(nodes uint32) error {
+	if lim.MaxNodes > 0 && uint64(nodes) > uint64(lim.MaxNodes) {
+		return fmt.Errorf("%w: %d nodes exceeds limit of %d", ErrInvalidData, nodes, lim.MaxNodes)
+	}
+	return nil
+}
+
+// checkShape checks that every node other than the root has at most one
+// parent, so the nodes form a tree: walking the map terminates and each
+// key is counted once. It also checks that no key is longer than the depth
+// limit, and returns the number of keys.
+// The store must already have been checked by checkNode.
+func (lim *LoadLimits) checkShape(store []byteValue) (int, error) {
+	seen := make([]bool, len(store))
+	type frame struct {
+		i    uint32 // node
+		next uint32 // next child to consider
+	}
+	stack := []frame{{}}
+	n := 0
+	seen[0] = true
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		bv := &store[f.i]
+		if f.next < uint32(bv.nextLen) {
+			c := bv.nextLo + f.next
+			f.next++
+			if seen[c] {
+				return 0, fmt.Errorf("%w: node %d has more than one parent", ErrInvalidData, c)
+			}
+			if lim.MaxDepth > 0 && len(stack) > lim.MaxDepth {
+				return 0, fmt.Errorf("%w: keys longer than limit of %d bytes", ErrInvalidData, lim.MaxDepth)
+			}
+			seen[c] = true
+			stack = append(stack, frame{i: c})
+			continue
+		}
+		if bv.valid {
+			n++
+		}
+		stack = stack[:len(stack)-1]
+	}
+	return n, nil
+}
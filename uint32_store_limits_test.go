@@ -0,0 +1,134 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+// persisted returns the persisted form of a map with the given nodes
+// each of which is nextLo, nextLen, nextOffset
+func persisted(nodes ...[3]uint32) []byte {
+	b := make([]byte, 16+12*len(nodes))
+	copy(b, "FSMU\x01")
+	binary.LittleEndian.PutUint32(b[8:], uint32(len(nodes)))
+	for i, n := range nodes {
+		d := b[16+12*i:]
+		binary.LittleEndian.PutUint32(d, n[0])
+		d[4], d[5] = byte(n[1]), byte(n[2])
+	}
+	binary.LittleEndian.PutUint32(b[12:], crc32.Checksum(b[16:], crc32.MakeTable(crc32.Castagnoli)))
+	return b
+}
+
+func TestReadUint32Store(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	var buf bytes.Buffer
+	if _, err := fm.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("after")
+	loaded, err := faststringmap.ReadUint32Store(&buf, faststringmap.LoadLimits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLookuper(t, "read", &loaded, ms)
+	if rest := buf.String(); rest != "after" {
+		t.Errorf("left %q unread want %q", rest, "after")
+	}
+
+	_, err = faststringmap.ReadUint32Store(bytes.NewReader(persisted([3]uint32{})[:20]), faststringmap.LoadLimits{})
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated got error %v want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestLoadLimits(t *testing.T) {
+	fm := faststringmap.NewUint32Store(faststringmap.Uint32MapSource{"a": 1, "abc": 2, "abcdef": 3})
+	b, _ := fm.MarshalBinary()
+	nodes := (len(b) - 16) / 12
+	for _, c := range []struct {
+		lim faststringmap.LoadLimits
+		ok  bool
+	}{
+		{faststringmap.LoadLimits{}, true},
+		{faststringmap.LoadLimits{MaxNodes: nodes, MaxDepth: 6}, true},
+		{faststringmap.LoadLimits{MaxNodes: nodes - 1}, false},
+		{faststringmap.LoadLimits{MaxDepth: 5}, false},
+	} {
+		var m faststringmap.Uint32Store
+		err := m.UnmarshalBinaryLimits(b, c.lim)
+		_, rerr := faststringmap.ReadUint32Store(bytes.NewReader(b), c.lim)
+		for _, err := range []error{err, rerr} {
+			if c.ok && err != nil {
+				t.Errorf("%+v: got error %v", c.lim, err)
+			} else if !c.ok && !errors.Is(err, faststringmap.ErrInvalidData) {
+				t.Errorf("%+v: got error %v want %v", c.lim, err, faststringmap.ErrInvalidData)
+			}
+		}
+	}
+}
+
+func TestLoadMalicious(t *testing.T) {
+	huge := persisted([3]uint32{})
+	binary.LittleEndian.PutUint32(huge[8:], 1<<30) // claims 12GiB of nodes
+	for name, bad := range map[string][]byte{
+		"self":   persisted([3]uint32{0, 1, 'a'}),
+		"cycle":  persisted([3]uint32{1, 1, 'a'}, [3]uint32{2, 1, 'b'}, [3]uint32{1, 1, 'c'}),
+		"offset": persisted([3]uint32{1, 2, 255}, [3]uint32{}, [3]uint32{}),
+		"huge":   huge,
+		// node 1 is reached by both "a" and "by", so Len would count its keys once
+		"shared": persisted([3]uint32{1, 2, 'a'}, [3]uint32{3, 1, 'x'}, [3]uint32{1, 1, 'y'}, [3]uint32{}),
+	} {
+		var m faststringmap.Uint32Store
+		if err := m.UnmarshalBinary(bad); !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("%s: UnmarshalBinary got error %v want %v", name, err, faststringmap.ErrInvalidData)
+		}
+		if _, err := faststringmap.ReadUint32Store(bytes.NewReader(bad), faststringmap.LoadLimits{}); err == nil {
+			t.Errorf("%s: ReadUint32Store got no error", name)
+		}
+	}
+}
+
+func TestBuildLimits(t *testing.T) {
+	src := faststringmap.Uint32MapSource{"a": 1, "abc": 2, "abcdef": 3, "\x00\xff": 4}
+	want := faststringmap.NewUint32Store(src)
+	nodes := want.MemStats().Nodes
+	nodeBytes := want.MemStats().NodeBytes
+	for _, c := range []struct {
+		lim faststringmap.BuildLimits
+		ok  bool
+	}{
+		{faststringmap.BuildLimits{}, true},
+		{faststringmap.BuildLimits{MaxNodes: nodes, MaxMemBytes: nodes * nodeBytes, MaxKeyLen: 6}, true},
+		{faststringmap.BuildLimits{MaxNodes: nodes - 1}, false},
+		{faststringmap.BuildLimits{MaxNodes: 3}, false},
+		{faststringmap.BuildLimits{MaxMemBytes: nodes*nodeBytes - 1}, false},
+		{faststringmap.BuildLimits{MaxKeyLen: 5}, false},
+	} {
+		fm, err := faststringmap.NewUint32StoreLimits(src, c.lim)
+		if c.ok {
+			if err != nil {
+				t.Errorf("%+v: got error %v", c.lim, err)
+			} else if fm.Fingerprint() != want.Fingerprint() || fm.Len() != want.Len() {
+				t.Errorf("%+v: map differs from NewUint32Store", c.lim)
+			}
+		} else if !errors.Is(err, faststringmap.ErrTooLarge) {
+			t.Errorf("%+v: got error %v want %v", c.lim, err, faststringmap.ErrTooLarge)
+		}
+	}
+
+	fm, err := faststringmap.NewUint32StoreLimits(faststringmap.Uint32MapSource{}, faststringmap.BuildLimits{MaxNodes: 1})
+	if err != nil || fm.Len() != 0 {
+		t.Errorf("empty: got %d keys and error %v", fm.Len(), err)
+	}
+}
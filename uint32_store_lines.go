@@ -0,0 +1,71 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// ReadUint32Source reads keys and values from r, one key and value on
+// each line separated by the last tab on the line, as read by
+// faststringmapgen. The value is a decimal uint32. A key starting with a
+// double quote is unquoted as a Go string literal, allowing any bytes in
+// keys. Blank lines are ignored. The error wraps ErrDuplicateKey if a key
+// appears more than once.
+func ReadUint32Source(r io.Reader) (Uint32MapSource, error) {
+	src := make(Uint32MapSource)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<24)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		tab := strings.LastIndexByte(text, '\t')
+		if tab < 0 {
+			return nil, fmt.Errorf("faststringmap: line %d: no tab before value", line)
+		}
+		key := text[:tab]
+		if strings.HasPrefix(key, `"`) {
+			var err error
+			if key, err = strconv.Unquote(key); err != nil {
+				return nil, fmt.Errorf("faststringmap: line %d: bad quoted key: %w", line, err)
+			}
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(text[tab+1:]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("faststringmap: line %d: %w", line, err)
+		}
+		if _, ok := src[key]; ok {
+			return nil, fmt.Errorf("%w on line %d: %q", ErrDuplicateKey, line, key)
+		}
+		src[key] = uint32(v)
+	}
+	return src, sc.Err()
+}
+
+// ReadUint32SourceFS reads keys and values like ReadUint32Source from the
+// named file in fsys, such as a dictionary shipped with a program in an
+// embed.FS:
+//
+//	//go:embed codes.txt
+//	var files embed.FS
+//
+//	src, err := faststringmap.ReadUint32SourceFS(files, "codes.txt")
+func ReadUint32SourceFS(fsys fs.FS, name string) (Uint32MapSource, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	src, err := ReadUint32Source(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return src, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestReadUint32Source(t *testing.T) {
+	input := "a\t1\n\n\"tab\\there\"\t2\r\nkey with spaces\t 4294967295\n\"\"\t3\n"
+	src, err := faststringmap.ReadUint32Source(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint32{"a": 1, "tab\there": 2, "key with spaces": 4294967295, "": 3}
+	if len(src) != len(want) {
+		t.Errorf("got %d keys want %d", len(src), len(want))
+	}
+	for k, v := range want {
+		if got, ok := src[k]; !ok || got != v {
+			t.Errorf("%q: got %d, %v want %d, true", k, got, ok, v)
+		}
+	}
+
+	_, err = faststringmap.ReadUint32Source(strings.NewReader("a\t1\nb\t2\na\t3\n"))
+	if !errors.Is(err, faststringmap.ErrDuplicateKey) {
+		t.Errorf("duplicate: got error %v want %v", err, faststringmap.ErrDuplicateKey)
+	}
+}
+
+func TestReadUint32SourceFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dict/codes.txt": {Data: []byte("GB\t1\nFR\t2\n")},
+		"dict/bad.txt":   {Data: []byte("GB 1\n")},
+	}
+	src, err := faststringmap.ReadUint32SourceFS(fsys, "dict/codes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm := faststringmap.NewUint32Store(src)
+	if v, ok := fm.LookupString("FR"); !ok || v != 2 || fm.Len() != 2 {
+		t.Errorf("FR: got %d, %v from %d keys want 2, true from 2", v, ok, fm.Len())
+	}
+
+	if _, err := faststringmap.ReadUint32SourceFS(fsys, "dict/bad.txt"); err == nil || !strings.Contains(err.Error(), "dict/bad.txt") {
+		t.Errorf("bad: got error %v want one naming the file", err)
+	}
+	if _, err := faststringmap.ReadUint32SourceFS(fsys, "dict/missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing: got error %v want %v", err, fs.ErrNotExist)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+// Uint32MapSource is a Uint32Source for a built-in Go map
+type Uint32MapSource map[string]uint32
+
+// AppendKeys appends the keys of the map to a and returns the resulting slice
+func (s Uint32MapSource) AppendKeys(a []string) []string {
+	for k := range s {
+		a = append(a, k)
+	}
+	return a
+}
+
+// Get returns the value for key k
+func (s Uint32MapSource) Get(k string) uint32 { return s[k] }
+
+// NewUint32StoreFromMap creates from the contents of the built-in map m
+func NewUint32StoreFromMap(m map[string]uint32) Uint32Store {
+	return NewUint32Store(Uint32MapSource(m))
+}
+
+// NewUint32StoreFromMapBuf creates from the contents of the built-in map m
+// using keyBuf for the internal slice of keys, rather than allocating one,
+// if it has enough capacity. It returns the slice used so that it can be
+// passed to later calls.
+func NewUint32StoreFromMapBuf(m map[string]uint32, keyBuf []string) (Uint32Store, []string) {
+	keys := Uint32MapSource(m).AppendKeys(keyBuf[:0])
+	sort.Strings(keys)
+	fm := newUint32StoreSorted(keys, func(i int) uint32 { return m[keys[i]] })
+	// do not keep the keys reachable via the returned buffer
+	for i := range keys {
+		keys[i] = ""
+	}
+	return fm, keys[:0]
+}
+
+// ToGoMap returns a built-in map with the same contents as m
+func (m *Uint32Store) ToGoMap() map[string]uint32 {
+	gm := make(map[string]uint32, m.Len())
+	m.walk(func(key []byte, v uint32) bool {
+		gm[string(key)] = v
+		return true
+	})
+	return gm
+}
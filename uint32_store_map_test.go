@@ -0,0 +1,38 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNewUint32StoreFromMap(t *testing.T) {
+	m := randomSmallStrings(1000, 8)
+	want := faststringmap.NewUint32Store(mapSliceN(m, len(m)))
+
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	if fm.Fingerprint() != want.Fingerprint() {
+		t.Error("NewUint32StoreFromMap differs from NewUint32Store")
+	}
+
+	buf := make([]string, 0, len(m))
+	fm, buf2 := faststringmap.NewUint32StoreFromMapBuf(m, buf)
+	if fm.Fingerprint() != want.Fingerprint() {
+		t.Error("NewUint32StoreFromMapBuf differs from NewUint32Store")
+	}
+	if cap(buf2) != cap(buf) || &buf2[:1][0] != &buf[:1][0] {
+		t.Error("key buffer not reused")
+	}
+
+	if got := fm.ToGoMap(); !reflect.DeepEqual(got, m) {
+		t.Errorf("ToGoMap gave %d entries want %d", len(got), len(m))
+	}
+
+	fm, _ = faststringmap.NewUint32StoreFromMapBuf(map[string]uint32{"a": 1}, nil)
+	if v, ok := fm.LookupString("a"); !ok || v != 1 {
+		t.Errorf("got %d, %v want 1, true", v, ok)
+	}
+}
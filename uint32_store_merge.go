@@ -0,0 +1,207 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// Merge returns a map with the keys of both a and b, which are not
+// modified. For a key in both the value is resolve applied to the values
+// from a and b. The tries are merged directly, without collecting and
+// sorting the keys as NewUint32Store would.
+func Merge(a, b *Uint32Store, resolve func(va, vb uint32) uint32) Uint32Store {
+	return merge(a, b, mergeUnion, resolve)
+}
+
+// Union is the same as Merge, named to go with Intersect and Difference
+func Union(a, b *Uint32Store, resolve func(va, vb uint32) uint32) Uint32Store {
+	return Merge(a, b, resolve)
+}
+
+// Intersect returns a map with the keys in both a and b, which are not
+// modified, with the value for each key given by resolve applied to the
+// values from a and b
+func Intersect(a, b *Uint32Store, resolve func(va, vb uint32) uint32) Uint32Store {
+	return merge(a, b, mergeIntersect, resolve)
+}
+
+// Difference returns a map with the keys in a which are not in b, with
+// their values from a. Neither a nor b is modified.
+func Difference(a, b *Uint32Store) Uint32Store {
+	return merge(a, b, mergeDifference, nil)
+}
+
+// mergeOp is the set operation performed by a merger
+type mergeOp int
+
+const (
+	mergeUnion mergeOp = iota
+	mergeIntersect
+	mergeDifference
+)
+
+func merge(a, b *Uint32Store, op mergeOp, resolve func(va, vb uint32) uint32) Uint32Store {
+	mg := merger{
+		a:       a.store,
+		b:       b.store,
+		op:      op,
+		resolve: resolve,
+		to:      make([]byteValue, 1, len(a.store)+len(b.store)+1),
+	}
+	mg.merge(rootIndex(a), rootIndex(b))
+	to := make([]byteValue, len(mg.to))
+	copy(to, mg.to)
+	return Uint32Store{store: to, n: mg.n}
+}
+
+// rootIndex returns the index of the root of m, or -1 if it is empty
+func rootIndex(m *Uint32Store) int {
+	if len(m.store) == 0 {
+		return -1
+	}
+	return int(m.root)
+}
+
+type (
+	// merger is used only during merge
+	merger struct {
+		a, b    []byteValue
+		op      mergeOp
+		resolve func(va, vb uint32) uint32
+		to      []byteValue
+		n       int // number of keys in to
+	}
+
+	// mergeFrame is a node being visited by merger.merge
+	mergeFrame struct {
+		at       uint32    // index in to
+		bva, bvb byteValue // the nodes being merged, empty if absent
+		lo, hi   int       // range of next bytes
+		c        int       // next byte to visit
+		nextLo   uint32    // index in to of the next nodes
+		followed bool      // whether any next node visited leads to keys
+	}
+)
+
+// merge sets to[0] to the result of the operation on a[ia] and b[ib],
+// where an index of -1 means there is no node on that side, and adds the
+// resulting next nodes, using an explicit stack like walkNodesFrom
+func (mg *merger) merge(ia, ib int) {
+	var frames [walkStackSize]mergeFrame
+	stack := append(frames[:0], mg.enter(0, ia, ib))
+	for {
+		f := &stack[len(stack)-1]
+		if c := f.c; c < f.hi {
+			f.c++
+			na, nb := nextIndex(&f.bva, c), nextIndex(&f.bvb, c)
+			if (na >= 0 || nb >= 0) && (na >= 0 || mg.op == mergeUnion) && (nb >= 0 || mg.op != mergeIntersect) {
+				stack = append(stack, mg.enter(f.nextLo+uint32(c-f.lo), na, nb))
+			}
+			continue
+		}
+		followed := mg.leave(f)
+		if stack = stack[:len(stack)-1]; len(stack) == 0 {
+			return
+		}
+		if followed {
+			stack[len(stack)-1].followed = true
+		}
+	}
+}
+
+// enter sets to[at] to the result of the operation on a[ia] and b[ib] and
+// adds space for the next nodes
+func (mg *merger) enter(at uint32, ia, ib int) mergeFrame {
+	f := mergeFrame{at: at}
+	if ia >= 0 {
+		f.bva = mg.a[ia]
+	}
+	if ib >= 0 {
+		f.bvb = mg.b[ib]
+	}
+	bv := &mg.to[at]
+	switch {
+	case f.bva.valid && f.bvb.valid:
+		if mg.op != mergeDifference {
+			bv.valid, bv.value = true, mg.resolve(f.bva.value, f.bvb.value)
+		}
+	case f.bva.valid:
+		if mg.op != mergeIntersect {
+			bv.valid, bv.value = true, f.bva.value
+		}
+	case f.bvb.valid:
+		if mg.op == mergeUnion {
+			bv.valid, bv.value = true, f.bvb.value
+		}
+	}
+	if bv.valid {
+		mg.n++
+	}
+
+	f.lo, f.hi = mg.nextRange(&f.bva, &f.bvb)
+	if f.lo >= f.hi {
+		f.c, f.hi = f.lo, f.lo // nothing to visit
+		return f
+	}
+	f.c, f.nextLo = f.lo, uint32(len(mg.to))
+	bv.nextLo, bv.nextLen, bv.nextOffset = f.nextLo, uint16(f.hi-f.lo), byte(f.lo)
+	mg.to = append(mg.to, make([]byteValue, f.hi-f.lo)...) // bv is no longer valid
+	return f
+}
+
+// leave finishes to[f.at] once its next nodes have been visited and
+// returns whether it leads to any keys
+func (mg *merger) leave(f *mergeFrame) bool {
+	bv := &mg.to[f.at]
+	if f.lo == f.hi {
+		return bv.valid
+	}
+	if !f.followed {
+		// nothing follows, as can happen for Intersect and Difference,
+		// so drop the next nodes, which are the last ones added
+		mg.to = mg.to[:f.nextLo]
+		bv.nextLo, bv.nextLen, bv.nextOffset = 0, 0, 0
+		return bv.valid
+	}
+	return true
+}
+
+// nextRange returns the range of next bytes to consider after bva and bvb
+func (mg *merger) nextRange(bva, bvb *byteValue) (lo, hi int) {
+	loA, hiA := int(bva.nextOffset), int(bva.nextOffset)+int(bva.nextLen)
+	loB, hiB := int(bvb.nextOffset), int(bvb.nextOffset)+int(bvb.nextLen)
+	switch {
+	case mg.op == mergeDifference || bvb.nextLen == 0:
+		if mg.op == mergeIntersect {
+			return 0, 0
+		}
+		return loA, hiA
+	case bva.nextLen == 0:
+		if mg.op == mergeIntersect {
+			return 0, 0
+		}
+		return loB, hiB
+	case mg.op == mergeIntersect:
+		return maxInt(loA, loB), minInt(hiA, hiB)
+	}
+	return minInt(loA, loB), maxInt(hiA, hiB)
+}
+
+// nextIndex returns the index of the next node after bv for byte c, or -1
+func nextIndex(bv *byteValue, c int) int {
+	if j := c - int(bv.nextOffset); j >= 0 && j < int(bv.nextLen) {
+		return int(bv.nextLo) + j
+	}
+	return -1
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestMerge(t *testing.T) {
+	ma := randomSmallStrings(2000, 6)
+	mb := randomSmallStrings(2000, 6)
+	for k := range ma {
+		if len(mb) > 2500 {
+			break
+		}
+		mb[k] = 7 // some keys in both
+	}
+	a := faststringmap.NewUint32StoreFromMap(ma)
+	b := faststringmap.NewUint32StoreFromMap(mb)
+	b = b.WithKey("extra", 1) // leaves an unused range in b
+	mb["extra"] = 1
+
+	sum := func(va, vb uint32) uint32 { return va + vb }
+	merged := faststringmap.Merge(&a, &b, sum)
+
+	want := make(map[string]uint32, len(ma)+len(mb))
+	for k, v := range ma {
+		want[k] = v
+	}
+	for k, v := range mb {
+		if va, ok := want[k]; ok {
+			v = sum(va, v)
+		}
+		want[k] = v
+	}
+	ms := mapSliceN(want, len(want))
+	ms.out = []string{"extr", "extras", "\xff\xff\xff\xff\xff\xff\xff"}
+	checkLookuper(t, "merged", &merged, ms)
+
+	var empty faststringmap.Uint32Store
+	merged = faststringmap.Merge(&a, &empty, sum)
+	checkLookuper(t, "a", &merged, mapSliceN(ma, len(ma)))
+	merged = faststringmap.Merge(&empty, &b, sum)
+	checkLookuper(t, "b", &merged, mapSliceN(mb, len(mb)))
+	merged = faststringmap.Merge(&empty, &empty, sum)
+	checkLookuper(t, "empty", &merged, mapSlice{out: []string{"", "a"}})
+}
+
+func TestSetOperations(t *testing.T) {
+	ma := randomSmallStrings(2000, 6)
+	mb := randomSmallStrings(2000, 6)
+	n := 0
+	for k := range ma {
+		if n++; n > 500 {
+			break
+		}
+		mb[k] = 7 // some keys in both
+	}
+	// keys only leading to keys not in the other map
+	ma["shared-a"], mb["shared-b"], ma["shared"], mb["shared"] = 1, 2, 3, 4
+	a := faststringmap.NewUint32StoreFromMap(ma)
+	b := faststringmap.NewUint32StoreFromMap(mb)
+	first := func(va, _ uint32) uint32 { return va }
+	second := func(_, vb uint32) uint32 { return vb }
+
+	all := make([]string, 0, len(ma)+len(mb))
+	for k := range ma {
+		all = append(all, k)
+	}
+	for k := range mb {
+		all = append(all, k)
+	}
+	expect := func(keep func(inA, inB bool) bool, value func(k string) uint32) mapSlice {
+		ms := mapSlice{m: map[string]uint32{}}
+		for _, k := range all {
+			_, inA := ma[k]
+			_, inB := mb[k]
+			if _, done := ms.m[k]; done {
+				continue
+			}
+			if keep(inA, inB) {
+				ms.m[k] = value(k)
+				ms.in = append(ms.in, k)
+			} else {
+				ms.out = append(ms.out, k)
+			}
+		}
+		return ms
+	}
+
+	u := faststringmap.Union(&a, &b, second)
+	checkLookuper(t, "Union", &u, expect(func(inA, inB bool) bool { return inA || inB }, func(k string) uint32 {
+		if v, ok := mb[k]; ok {
+			return v
+		}
+		return ma[k]
+	}))
+	i := faststringmap.Intersect(&a, &b, first)
+	checkLookuper(t, "Intersect", &i, expect(func(inA, inB bool) bool { return inA && inB }, func(k string) uint32 { return ma[k] }))
+	d := faststringmap.Difference(&a, &b)
+	checkLookuper(t, "Difference", &d, expect(func(inA, inB bool) bool { return inA && !inB }, func(k string) uint32 { return ma[k] }))
+	d = faststringmap.Difference(&b, &a)
+	checkLookuper(t, "Difference b a", &d, expect(func(inA, inB bool) bool { return inB && !inA }, func(k string) uint32 { return mb[k] }))
+
+	// branches with no keys in the result are not kept
+	a = faststringmap.NewUint32StoreFromMap(map[string]uint32{"x": 1, "xabcdef": 2})
+	b = faststringmap.NewUint32StoreFromMap(map[string]uint32{"x": 3, "xabcxyz": 4})
+	i = faststringmap.Intersect(&a, &b, first)
+	if st := i.MemStats(); st.Nodes != 2 {
+		t.Errorf("Intersect kept %d nodes for one key", st.Nodes)
+	}
+	d = faststringmap.Difference(&a, &a)
+	if st := d.MemStats(); st.Keys != 0 || st.Nodes != 1 {
+		t.Errorf("Difference with itself got %d keys %d nodes", st.Keys, st.Nodes)
+	}
+}
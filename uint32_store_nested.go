@@ -0,0 +1,76 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoSeparator is returned (wrapped) when a composite key does not contain the separator
+var ErrNoSeparator = errors.New("faststringmap: key has no separator")
+
+// NestedUint32Store is a two level map from a pair of strings to uint32,
+// for example country code then region code to value. Each first level
+// key maps to its own Uint32Store holding the second level keys.
+type NestedUint32Store struct {
+	outer Uint32Store   // first level key to index in inner
+	inner []Uint32Store // second level maps
+}
+
+// NewNestedUint32Store creates from the composite keys supplied in src,
+// each of which is split at the first occurrence of sep into the first
+// and second level keys. It returns an error wrapping ErrNoSeparator if a
+// key does not contain sep, rather than losing it.
+func NewNestedUint32Store(src Uint32Source, sep byte) (NestedUint32Store, error) {
+	keys := src.AppendKeys([]string(nil))
+	groups := make(map[string][]string)
+	var firsts []string
+	for _, k := range keys {
+		i := strings.IndexByte(k, sep)
+		if i < 0 {
+			return NestedUint32Store{}, fmt.Errorf("%w: %q in %q", ErrNoSeparator, sep, k)
+		}
+		k1 := k[:i]
+		if _, ok := groups[k1]; !ok {
+			firsts = append(firsts, k1)
+		}
+		groups[k1] = append(groups[k1], k[i+1:])
+	}
+
+	index := make(map[string]uint32, len(firsts))
+	inner := make([]Uint32Store, len(firsts))
+	for i, k1 := range firsts {
+		index[k1] = uint32(i)
+		prefix := k1 + string(sep)
+		inner[i] = NewUint32Store(funcSource{
+			keys: groups[k1],
+			get:  func(k2 string) uint32 { return src.Get(prefix + k2) },
+		})
+	}
+	return NestedUint32Store{
+		outer: NewUint32Store(funcSource{
+			keys: firsts,
+			get:  func(k1 string) uint32 { return index[k1] },
+		}),
+		inner: inner,
+	}, nil
+}
+
+// Inner returns the second level map for the first level key k1
+func (m *NestedUint32Store) Inner(k1 string) (*Uint32Store, bool) {
+	i, ok := m.outer.LookupString(k1)
+	if !ok {
+		return nil, false
+	}
+	return &m.inner[i], true
+}
+
+// Lookup2Level looks up the second level key k2 in the map for the first level key k1
+func (m *NestedUint32Store) Lookup2Level(k1, k2 string) (uint32, bool) {
+	if i, ok := m.outer.LookupString(k1); ok {
+		return m.inner[i].LookupString(k2)
+	}
+	return 0, false
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNestedUint32Store(t *testing.T) {
+	m := map[string]uint32{
+		"uk:eng": 1,
+		"uk:sct": 2,
+		"uk:":    3,
+		"fr:idf": 4,
+		":de":    5,
+	}
+	fm, err := faststringmap.NewNestedUint32Store(mapSliceN(m, len(m)), ':')
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		k1, k2 string
+		value  uint32
+		ok     bool
+	}{
+		{"uk", "eng", 1, true},
+		{"uk", "sct", 2, true},
+		{"uk", "", 3, true},
+		{"fr", "idf", 4, true},
+		{"fr", "", 0, false},
+		{"uk", "idf", 0, false},
+		{"", "de", 5, true},
+		{"de", "", 0, false},
+		{"us", "ca", 0, false},
+	} {
+		v, ok := fm.Lookup2Level(tc.k1, tc.k2)
+		if v != tc.value || ok != tc.ok {
+			t.Errorf("%q, %q: got %d, %v want %d, %v", tc.k1, tc.k2, v, ok, tc.value, tc.ok)
+		}
+	}
+
+	if inner, ok := fm.Inner("fr"); !ok {
+		t.Error("fr not present")
+	} else if v, ok := inner.LookupString("idf"); !ok || v != 4 {
+		t.Errorf("fr inner: got %d, %v want 4, true", v, ok)
+	}
+	if _, ok := fm.Inner("de"); ok {
+		t.Error("de present when not expected")
+	}
+}
+
+func TestNestedUint32StoreNoSeparator(t *testing.T) {
+	m := map[string]uint32{"uk:eng": 1, "de": 2}
+	if _, err := faststringmap.NewNestedUint32Store(mapSliceN(m, len(m)), ':'); !errors.Is(err, faststringmap.ErrNoSeparator) {
+		t.Errorf("got error %v want %v", err, faststringmap.ErrNoSeparator)
+	}
+}
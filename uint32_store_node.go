@@ -0,0 +1,70 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// NodeID identifies the position in a map reached by following a prefix,
+// so that lookups of keys sharing a known prefix only need to follow the
+// rest of the key. A NodeID is only meaningful for the map it came from.
+type NodeID uint32
+
+// NoNode is returned when a prefix is not in the trie
+const NoNode NodeID = ^NodeID(0)
+
+// Root returns the NodeID for the empty prefix, which is NoNode for the
+// zero value. It is not the same for every map as the root of a map made
+// by WithKey or WithoutKey is not the first node in the store.
+func (m *Uint32Store) Root() NodeID {
+	if len(m.store) == 0 {
+		return NoNode
+	}
+	return NodeID(m.root)
+}
+
+// Node returns the NodeID reached by following prefix, ok is false if
+// no key in the map starts with prefix
+func (m *Uint32Store) Node(prefix string) (NodeID, bool) {
+	if len(m.store) == 0 {
+		return NoNode, false
+	}
+	if i, ok := m.follow(m.root, prefix); ok && !m.store[i].isEmpty() {
+		return NodeID(i), true
+	}
+	return NoNode, false
+}
+
+// LookupFrom looks up the key made up of the prefix for h followed by
+// suffix, returning its value and the NodeID for the key, which is NoNode
+// if no key in the map starts with the key
+func (m *Uint32Store) LookupFrom(h NodeID, suffix []byte) (v uint32, next NodeID, ok bool) {
+	if h == NoNode || len(m.store) == 0 {
+		return 0, NoNode, false
+	}
+	i := uint32(h)
+	for _, b := range suffix {
+		if i, ok = m.step(i, b); !ok {
+			return 0, NoNode, false
+		}
+	}
+	bv := &m.store[i]
+	if bv.isEmpty() {
+		return 0, NoNode, false
+	}
+	return bv.value, NodeID(i), bv.valid
+}
+
+// LookupStringFrom is like LookupFrom for a string suffix
+func (m *Uint32Store) LookupStringFrom(h NodeID, suffix string) (v uint32, next NodeID, ok bool) {
+	if h == NoNode || len(m.store) == 0 {
+		return 0, NoNode, false
+	}
+	i, found := m.follow(uint32(h), suffix)
+	if !found || m.store[i].isEmpty() {
+		return 0, NoNode, false
+	}
+	bv := &m.store[i]
+	return bv.value, NodeID(i), bv.valid
+}
+
+// isEmpty reports whether bv is an unused slot in a range of next nodes,
+// which is neither a key nor leads to any further nodes
+func (bv *byteValue) isEmpty() bool { return !bv.valid && bv.nextLen == 0 }
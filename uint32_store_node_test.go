@@ -0,0 +1,97 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestLookupFrom(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"metrics.cpu.user": 1,
+		"metrics.cpu.sys":  2,
+		"metrics.cpu":      3,
+		"metrics.mem":      4,
+	})
+	h, ok := fm.Node("metrics.cpu")
+	if !ok {
+		t.Fatal("metrics.cpu not found")
+	}
+	for _, tc := range []struct {
+		suffix string
+		value  uint32
+		ok     bool
+	}{
+		{".user", 1, true},
+		{".sys", 2, true},
+		{"", 3, true},
+		{".", 0, false},
+		{".idle", 0, false},
+	} {
+		if v, _, ok := fm.LookupFrom(h, []byte(tc.suffix)); v != tc.value || ok != tc.ok {
+			t.Errorf("LookupFrom %q: got %d, %v want %d, %v", tc.suffix, v, ok, tc.value, tc.ok)
+		}
+		if v, _, ok := fm.LookupStringFrom(h, tc.suffix); v != tc.value || ok != tc.ok {
+			t.Errorf("LookupStringFrom %q: got %d, %v want %d, %v", tc.suffix, v, ok, tc.value, tc.ok)
+		}
+	}
+
+	// resume from the node returned by a lookup
+	_, dot, ok := fm.LookupFrom(h, []byte("."))
+	if ok || dot == faststringmap.NoNode {
+		t.Fatalf("LookupFrom . got %v, %v", dot, ok)
+	}
+	if v, _, ok := fm.LookupStringFrom(dot, "sys"); !ok || v != 2 {
+		t.Errorf("resumed lookup got %d, %v want 2, true", v, ok)
+	}
+	if _, next, _ := fm.LookupFrom(h, []byte(".idle")); next != faststringmap.NoNode {
+		t.Errorf("diverged lookup got node %v want NoNode", next)
+	}
+	if v, _, ok := fm.LookupFrom(faststringmap.NoNode, nil); ok {
+		t.Errorf("lookup from NoNode got %d, true", v)
+	}
+	if _, ok := fm.Node("metrics.disk"); ok {
+		t.Error("metrics.disk found")
+	}
+
+	// t is between s and u so has an unused slot in the range after "."
+	if _, ok := fm.Node("metrics.cpu.t"); ok {
+		t.Error("metrics.cpu.t found")
+	}
+	if _, next, _ := fm.LookupFrom(h, []byte(".t")); next != faststringmap.NoNode {
+		t.Errorf("unused slot got node %v want NoNode", next)
+	}
+	if _, next, _ := fm.LookupStringFrom(h, ".t"); next != faststringmap.NoNode {
+		t.Errorf("unused slot got node %v want NoNode", next)
+	}
+	if v, _, ok := fm.LookupStringFrom(fm.Root(), "metrics.mem"); !ok || v != 4 {
+		t.Errorf("lookup from root got %d, %v want 4, true", v, ok)
+	}
+
+	var zero faststringmap.Uint32Store
+	if zero.Root() != faststringmap.NoNode {
+		t.Errorf("zero map Root got %v want NoNode", zero.Root())
+	}
+}
+
+func TestLookupFromRootAfterWithKey(t *testing.T) {
+	orig := faststringmap.NewUint32StoreFromMap(map[string]uint32{"a": 1, "c": 3})
+	added := orig.WithKey("b", 2)
+	for k, want := range map[string]uint32{"a": 1, "b": 2, "c": 3} {
+		if v, _, ok := added.LookupStringFrom(added.Root(), k); !ok || v != want {
+			t.Errorf("added %q: got %d, %v want %d, true", k, v, ok, want)
+		}
+	}
+	removed := added.WithoutKey("a")
+	if _, _, ok := removed.LookupFrom(removed.Root(), []byte("a")); ok {
+		t.Error("removed a: found from Root")
+	}
+	if h, ok := removed.Node(""); !ok || h != removed.Root() {
+		t.Errorf("Node(\"\") got %v, %v want %v, true", h, ok, removed.Root())
+	}
+	if v, _, ok := removed.LookupStringFrom(removed.Root(), "b"); !ok || v != 2 {
+		t.Errorf("removed b: got %d, %v want 2, true", v, ok)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// MinKey returns the smallest key in the map and its value
+func (m *Uint32Store) MinKey() (key string, v uint32, ok bool) {
+	if len(m.store) == 0 {
+		return "", 0, false
+	}
+	k, v, ok := m.minFrom(m.root, make([]byte, 0, 64))
+	return string(k), v, ok
+}
+
+// MaxKey returns the largest key in the map and its value
+func (m *Uint32Store) MaxKey() (key string, v uint32, ok bool) {
+	if len(m.store) == 0 {
+		return "", 0, false
+	}
+	k, v, ok := m.maxFrom(m.root, make([]byte, 0, 64))
+	return string(k), v, ok
+}
+
+// NextKey returns the smallest key in the map which is greater than s,
+// which need not be in the map, and its value
+func (m *Uint32Store) NextKey(s string) (key string, v uint32, ok bool) {
+	path, depth := m.path(s)
+	if path == nil {
+		return "", 0, false
+	}
+	buf := append(make([]byte, 0, len(s)+64), s...)
+	if depth == len(s) {
+		// keys below s itself are greater than it
+		if k, v, ok := m.minBelow(path[depth], buf[:depth]); ok {
+			return string(k), v, true
+		}
+		depth--
+	}
+	for d := depth; d >= 0; d-- {
+		bv := &m.store[path[d]]
+		for j := 0; j < int(bv.nextLen); j++ {
+			c := bv.nextOffset + byte(j)
+			if c <= s[d] {
+				continue
+			}
+			if k, v, ok := m.minFrom(bv.nextLo+uint32(j), append(buf[:d], c)); ok {
+				return string(k), v, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// PrevKey returns the largest key in the map which is less than s,
+// which need not be in the map, and its value
+func (m *Uint32Store) PrevKey(s string) (key string, v uint32, ok bool) {
+	path, depth := m.path(s)
+	if path == nil {
+		return "", 0, false
+	}
+	buf := append(make([]byte, 0, len(s)+64), s...)
+	if depth == len(s) {
+		// s itself and keys below it are not less than s
+		depth--
+	}
+	for d := depth; d >= 0; d-- {
+		bv := &m.store[path[d]]
+		for j := int(bv.nextLen) - 1; j >= 0; j-- {
+			c := bv.nextOffset + byte(j)
+			if c >= s[d] {
+				continue
+			}
+			if k, v, ok := m.maxFrom(bv.nextLo+uint32(j), append(buf[:d], c)); ok {
+				return string(k), v, true
+			}
+		}
+		// a proper prefix of s is less than s
+		if bv.valid {
+			return string(buf[:d]), bv.value, true
+		}
+	}
+	return "", 0, false
+}
+
+// path returns the store indexes of the byteValues for each prefix of s
+// which can be followed in the trie, so path[d] is for s[:d], and the
+// length of the longest such prefix. path is nil for an empty map.
+func (m *Uint32Store) path(s string) (path []uint32, depth int) {
+	if len(m.store) == 0 {
+		return nil, 0
+	}
+	path = append(make([]uint32, 0, len(s)+1), m.root)
+	for depth < len(s) {
+		i, ok := m.step(path[depth], s[depth])
+		if !ok {
+			break
+		}
+		path = append(path, i)
+		depth++
+	}
+	return path, depth
+}
+
+// minFrom returns the smallest key in the sub-trie rooted at store index i,
+// where key is the byte sequence leading to it
+func (m *Uint32Store) minFrom(i uint32, key []byte) (k []byte, v uint32, ok bool) {
+	m.walkFrom(i, key, func(key []byte, value uint32) bool {
+		k, v, ok = key, value, true
+		return false
+	})
+	return k, v, ok
+}
+
+// minBelow returns the smallest key in the sub-trie rooted at store index i
+// excluding the key for i itself
+func (m *Uint32Store) minBelow(i uint32, key []byte) ([]byte, uint32, bool) {
+	bv := &m.store[i]
+	for j := uint32(0); j < uint32(bv.nextLen); j++ {
+		if k, v, ok := m.minFrom(bv.nextLo+j, append(key, bv.nextOffset+byte(j))); ok {
+			return k, v, true
+		}
+	}
+	return nil, 0, false
+}
+
+// maxFrom returns the largest key in the sub-trie rooted at store index i,
+// where key is the byte sequence leading to it
+func (m *Uint32Store) maxFrom(i uint32, key []byte) (k []byte, v uint32, ok bool) {
+	m.walkDescFrom(i, key, func(key []byte, value uint32) bool {
+		k, v, ok = key, value, true
+		return false
+	})
+	return k, v, ok
+}
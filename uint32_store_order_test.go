@@ -0,0 +1,82 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNextPrevKey(t *testing.T) {
+	m := randomSmallStrings(500, 4)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	keys := append([]string(nil), ms.in...)
+	sort.Strings(keys)
+
+	probes := append(append([]string{"", "\x00", "~~~~~~"}, ms.in...), ms.out...)
+	for _, s := range probes {
+		i := sort.SearchStrings(keys, s) // first key >= s
+		j := i
+		if j < len(keys) && keys[j] == s {
+			j++
+		}
+		k, v, ok := fm.NextKey(s)
+		if j < len(keys) {
+			if !ok || k != keys[j] || v != m[k] {
+				t.Errorf("NextKey %q: got %q, %d, %v want %q, %d, true", s, k, v, ok, keys[j], m[keys[j]])
+			}
+		} else if ok {
+			t.Errorf("NextKey %q: got %q, %d, true want none", s, k, v)
+		}
+
+		k, v, ok = fm.PrevKey(s)
+		if i > 0 {
+			if !ok || k != keys[i-1] || v != m[k] {
+				t.Errorf("PrevKey %q: got %q, %d, %v want %q, %d, true", s, k, v, ok, keys[i-1], m[keys[i-1]])
+			}
+		} else if ok {
+			t.Errorf("PrevKey %q: got %q, %d, true want none", s, k, v)
+		}
+	}
+
+	var empty faststringmap.Uint32Store
+	if _, _, ok := empty.NextKey(""); ok {
+		t.Error("NextKey found key in empty map")
+	}
+	if _, _, ok := empty.PrevKey("a"); ok {
+		t.Error("PrevKey found key in empty map")
+	}
+}
+
+func TestMinMaxKey(t *testing.T) {
+	m := randomSmallStrings(500, 6)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	sorted := append([]string(nil), ms.in...)
+	sort.Strings(sorted)
+	if k, v, ok := fm.MinKey(); !ok || k != sorted[0] || v != m[k] {
+		t.Errorf("MinKey got %q, %d, %v want %q", k, v, ok, sorted[0])
+	}
+	if k, v, ok := fm.MaxKey(); !ok || k != sorted[len(sorted)-1] || v != m[k] {
+		t.Errorf("MaxKey got %q, %d, %v want %q", k, v, ok, sorted[len(sorted)-1])
+	}
+
+	fm = faststringmap.NewUint32StoreFromMap(map[string]uint32{"b": 1, "ba": 2, "bab": 3})
+	if k, _, _ := fm.MinKey(); k != "b" {
+		t.Errorf("MinKey got %q want b", k)
+	}
+	if k, _, _ := fm.MaxKey(); k != "bab" {
+		t.Errorf("MaxKey got %q want bab", k)
+	}
+	for _, empty := range []faststringmap.Uint32Store{{}, faststringmap.NewUint32StoreFromMap(nil)} {
+		if _, _, ok := empty.MinKey(); ok {
+			t.Error("MinKey of empty map ok")
+		}
+		if _, _, ok := empty.MaxKey(); ok {
+			t.Error("MaxKey of empty map ok")
+		}
+	}
+}
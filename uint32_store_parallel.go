@@ -0,0 +1,185 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewUint32StoreParallel creates from the data supplied in src like
+// NewUint32Store but using up to workers goroutines, or GOMAXPROCS if
+// workers is less than 1. The keys are partitioned by their first byte
+// and each partition is sorted and built into a sub-trie concurrently,
+// then the sub-tries are joined under the root. src.Get is called from
+// several goroutines at once so must be safe for concurrent use, as it is
+// for Uint32MapSource.
+func NewUint32StoreParallel(src Uint32Source, workers int) Uint32Store {
+	keys := src.AppendKeys([]string(nil))
+	var root byteValue
+	var parts [256][]string // keys by first byte
+	for _, k := range keys {
+		if k == "" {
+			root.valid, root.value = true, src.Get(k)
+			continue
+		}
+		parts[k[0]] = append(parts[k[0]], k)
+	}
+	var counts [256]int
+	for c := range parts {
+		counts[c] = len(parts[c])
+	}
+	store, _ := buildByFirstByte(root, &counts, workers, func(c int) ([]byteValue, error) {
+		part := parts[c]
+		sort.Strings(part)
+		return uint32Build(trimFirstByte(part), func(i int) uint32 { return src.Get(part[i]) }), nil
+	})
+	return Uint32Store{store: store, n: len(keys)}
+}
+
+// BuildParallel creates a Uint32Store from the keys and values added so
+// far like Build but using up to n goroutines, or GOMAXPROCS if n is less
+// than 1, each sorting and building the keys starting with one byte at a time.
+// It returns an error wrapping ErrDuplicateKey if a key was added more than once.
+func (b *Uint32StoreBuilder) BuildParallel(n int) (Uint32Store, error) {
+	start := time.Now()
+	var root byteValue
+	var parts [256][]builderEntry // entries by first byte
+	total := 0
+	for i := range b.shards {
+		sh := &b.shards[i]
+		sh.mu.Lock()
+		for _, e := range sh.entries {
+			if e.key != "" {
+				parts[e.key[0]] = append(parts[e.key[0]], e)
+			} else if root.valid {
+				sh.mu.Unlock()
+				return Uint32Store{}, fmt.Errorf("%w: %q", ErrDuplicateKey, e.key)
+			} else {
+				root.valid, root.value = true, e.value
+			}
+		}
+		total += len(sh.entries)
+		sh.mu.Unlock()
+	}
+	var counts [256]int
+	for c := range parts {
+		counts[c] = len(parts[c])
+	}
+	r := b.newReporter(start, total)
+	store, err := buildByFirstByte(root, &counts, n, func(c int) ([]byteValue, error) {
+		part, keys := parts[c], make([]string, len(parts[c]))
+		sort.Slice(part, func(i, j int) bool { return part[i].key < part[j].key })
+		for i, e := range part {
+			if i > 0 && e.key == part[i-1].key {
+				return nil, fmt.Errorf("%w: %q", ErrDuplicateKey, e.key)
+			}
+			keys[i] = e.key[1:]
+		}
+		return uint32BuildReporting(keys, func(i int) uint32 { return part[i].value }, r), nil
+	})
+	if err != nil {
+		return Uint32Store{}, err
+	}
+	if r != nil {
+		r.finish(len(store))
+	}
+	return Uint32Store{store: store, n: total}, nil
+}
+
+// buildByFirstByte creates the nodes of a map whose root is root by
+// calling build for each byte c for which counts[c] is not zero, on up to
+// workers goroutines, or GOMAXPROCS if workers is less than 1. build
+// returns the sub-trie for the keys starting with c built without that
+// byte, and these are joined under the root. If build returns an error
+// then the error for the lowest byte is returned.
+func buildByFirstByte(root byteValue, counts *[256]int, workers int,
+	build func(c int) ([]byteValue, error)) ([]byteValue, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	lo, hi := 256, -1
+	for c, n := range counts {
+		if n > 0 {
+			lo, hi = minInt(lo, c), maxInt(hi, c)
+		}
+	}
+	if hi < 0 {
+		return []byteValue{root}, nil
+	}
+
+	var subs [256][]byteValue
+	var errs [256]error
+	inParallel(lo, hi, workers, counts, func(c int) { subs[c], errs[c] = build(c) })
+	for c := lo; c <= hi; c++ {
+		if errs[c] != nil {
+			return nil, errs[c]
+		}
+	}
+
+	// the root, then its range of next nodes, then the rest of each
+	// sub-trie in order of first byte, moving their indexes to match
+	root.nextLo, root.nextLen, root.nextOffset = 1, uint16(hi-lo+1), byte(lo)
+	size := 1 + hi - lo + 1
+	var bases [256]uint32
+	for c := lo; c <= hi; c++ {
+		bases[c] = uint32(size)
+		if len(subs[c]) > 0 {
+			size += len(subs[c]) - 1
+		}
+	}
+	store := make([]byteValue, size)
+	store[0] = root
+	inParallel(lo, hi, workers, counts, func(c int) {
+		sub, base := subs[c], bases[c]
+		moveSubTrie(store[1+c-lo:2+c-lo], sub[:1], base)
+		moveSubTrie(store[base:base+uint32(len(sub)-1)], sub[1:], base)
+	})
+	return store, nil
+}
+
+// inParallel calls f for each byte c from lo to hi for which counts[c] is
+// not zero, on workers goroutines, and waits for them all to return
+func inParallel(lo, hi, workers int, counts *[256]int, f func(c int)) {
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range work {
+				f(c)
+			}
+		}()
+	}
+	for c := lo; c <= hi; c++ {
+		if counts[c] > 0 {
+			work <- c
+		}
+	}
+	close(work)
+	wg.Wait()
+}
+
+// trimFirstByte returns the keys, which must not be empty, without their first byte
+func trimFirstByte(keys []string) []string {
+	trimmed := make([]string, len(keys))
+	for i, k := range keys {
+		trimmed[i] = k[1:]
+	}
+	return trimmed
+}
+
+// moveSubTrie copies the nodes of a sub-trie in from to to, where the
+// nodes after the root of the sub-trie, at index 1, are to start at base
+func moveSubTrie(to, from []byteValue, base uint32) {
+	copy(to, from)
+	for i := range to {
+		if to[i].nextLen > 0 {
+			to[i].nextLo += base - 1
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNewUint32StoreParallel(t *testing.T) {
+	m := randomSmallStrings(4096, 8)
+	for i := 0; i < 256; i++ {
+		m[string([]byte{byte(i)})] = uint32(i)
+	}
+	want := faststringmap.NewUint32StoreFromMap(m)
+	for _, workers := range []int{0, 1, 3, 16} {
+		fm := faststringmap.NewUint32StoreParallel(faststringmap.Uint32MapSource(m), workers)
+		if fm.Fingerprint() != want.Fingerprint() || fm.Len() != len(m) {
+			t.Errorf("workers %d: map differs from NewUint32Store", workers)
+		}
+		for k, v := range m {
+			if got, ok := fm.LookupString(k); !ok || got != v {
+				t.Errorf("workers %d: %q got %d, %v want %d, true", workers, k, got, ok, v)
+			}
+		}
+		if v, ok := fm.LookupString("\x00\x00\x00\x00\x00\x00\x00\x00\x00"); ok {
+			t.Errorf("workers %d: unexpected key present with value %d", workers, v)
+		}
+	}
+}
+
+func TestNewUint32StoreParallelSmall(t *testing.T) {
+	for _, m := range []map[string]uint32{{}, {"": 1}, {"a": 2}, {"": 1, "ab": 2, "ac": 3}} {
+		fm := faststringmap.NewUint32StoreParallel(faststringmap.Uint32MapSource(m), 2)
+		want := faststringmap.NewUint32StoreFromMap(m)
+		if fm.Fingerprint() != want.Fingerprint() || fm.Len() != len(m) {
+			t.Errorf("%v: map differs from NewUint32Store", m)
+		}
+	}
+}
+
+func TestUint32StoreBuilderBuildParallel(t *testing.T) {
+	m := randomSmallStrings(4096, 8)
+	var b faststringmap.Uint32StoreBuilder
+	for k, v := range m {
+		b.Add(k, v)
+	}
+	fm, err := b.BuildParallel(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := faststringmap.NewUint32StoreFromMap(m)
+	if fm.Fingerprint() != want.Fingerprint() || fm.Len() != len(m) {
+		t.Error("built map differs from NewUint32Store")
+	}
+
+	for _, k := range []string{"", "x"} {
+		var b faststringmap.Uint32StoreBuilder
+		b.Add(k, 0)
+		b.Add("y", 1)
+		b.Add(k, 2)
+		if _, err := b.BuildParallel(0); !errors.Is(err, faststringmap.ErrDuplicateKey) {
+			t.Errorf("%q: got error %v want %v", k, err, faststringmap.ErrDuplicateKey)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Persisted format, all integers little-endian:
+//
+//	header (persistHeaderSize bytes):
+//	  magic      [4]byte "FSMU"
+//	  version    uint8
+//	  reserved   [3]byte
+//	  nodes      uint32  number of byteValues
+//	  checksum   uint32  CRC-32C of the node data
+//	node data, persistNodeSize bytes for each byteValue:
+//	  nextLo     uint32
+//	  nextLen    uint8   low 8 bits of nextLen
+//	  nextOffset uint8
+//	  flags      uint8   bit 0 set if valid, bit 1 is bit 8 of nextLen
+//	  reserved   uint8
+//	  value      uint32
+const (
+	persistMagic      = "FSMU"
+	persistVersion    = 1
+	persistHeaderSize = 16
+	persistNodeSize   = 12
+)
+
+// ErrInvalidData is returned (wrapped) when persisted data is malformed
+var ErrInvalidData = errors.New("faststringmap: invalid persisted data")
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// WriteTo writes the map to w in a form which can be loaded without rebuilding
+func (m *Uint32Store) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(m.appendBinary(nil))
+	return int64(n), err
+}
+
+// MarshalBinary returns the map in a form which can be loaded without rebuilding
+func (m *Uint32Store) MarshalBinary() ([]byte, error) {
+	return m.appendBinary(nil), nil
+}
+
+// appendBinary appends the persisted form of the map to b
+func (m *Uint32Store) appendBinary(b []byte) []byte {
+	store := m.rooted().store
+	if len(store) == 0 {
+		store = []byteValue{{}}
+	}
+	start := len(b)
+	b = append(b, make([]byte, persistHeaderSize+len(store)*persistNodeSize)...)
+	hdr, data := b[start:start+persistHeaderSize], b[start+persistHeaderSize:]
+	for i := range store {
+		putNode(data[i*persistNodeSize:], &store[i])
+	}
+	copy(hdr, persistMagic)
+	hdr[4] = persistVersion
+	binary.LittleEndian.PutUint32(hdr[8:], uint32(len(store)))
+	binary.LittleEndian.PutUint32(hdr[12:], crc32.Checksum(data, crcTable))
+	return b
+}
+
+// ReadFrom loads a map written by WriteTo from r, reading no further than
+// the end of the map. Use ReadUint32Store to limit the size of the map.
+func (m *Uint32Store) ReadFrom(r io.Reader) (int64, error) {
+	cr := countingReader{r: r}
+	loaded, err := ReadUint32Store(&cr, LoadLimits{})
+	if err == nil {
+		*m = loaded
+	}
+	return cr.n, err
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(b []byte) (int, error) {
+	n, err := cr.r.Read(b)
+	cr.n += int64(n)
+	return n, err
+}
+
+// UnmarshalBinary loads a map from data returned by MarshalBinary or written by WriteTo
+func (m *Uint32Store) UnmarshalBinary(data []byte) error {
+	return m.UnmarshalBinaryLimits(data, LoadLimits{})
+}
+
+// MustUnmarshalUint32Store loads a map from data returned by MarshalBinary
+// or written by WriteTo, panicking if data is malformed. It is intended for
+// initialising package level variables from constants, for example in Go
+// source written by the faststringmapgen command. Like UnmarshalBinary it
+// checks and decodes every node into a new slice, so it takes time and
+// memory in proportion to the size of the map when the package is
+// initialised.
+func MustUnmarshalUint32Store(data string) Uint32Store {
+	var m Uint32Store
+	if err := m.UnmarshalBinary([]byte(data)); err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func putNode(b []byte, bv *byteValue) {
+	binary.LittleEndian.PutUint32(b, bv.nextLo)
+	b[4] = byte(bv.nextLen)
+	b[5] = bv.nextOffset
+	b[6] = byte(bv.nextLen>>8) << 1
+	if bv.valid {
+		b[6] |= 1
+	}
+	b[7] = 0
+	binary.LittleEndian.PutUint32(b[8:], bv.value)
+}
+
+func getNode(b []byte) byteValue {
+	return byteValue{
+		nextLo:     binary.LittleEndian.Uint32(b),
+		nextLen:    uint16(b[4]) | uint16(b[6]&2)<<7,
+		nextOffset: b[5],
+		valid:      b[6]&1 != 0,
+		value:      binary.LittleEndian.Uint32(b[8:]),
+	}
+}
+
+// persistHeader is the decoded header of persisted data
+type persistHeader struct {
+	nodes    uint32
+	checksum uint32
+}
+
+// parseHeader checks and decodes a persisted header
+func parseHeader(hdr []byte) (persistHeader, error) {
+	if len(hdr) < persistHeaderSize || string(hdr[:4]) != persistMagic {
+		return persistHeader{}, fmt.Errorf("%w: bad magic", ErrInvalidData)
+	}
+	if hdr[4] != persistVersion {
+		return persistHeader{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidData, hdr[4])
+	}
+	h := persistHeader{
+		nodes:    binary.LittleEndian.Uint32(hdr[8:]),
+		checksum: binary.LittleEndian.Uint32(hdr[12:]),
+	}
+	if h.nodes == 0 {
+		return persistHeader{}, fmt.Errorf("%w: no nodes", ErrInvalidData)
+	}
+	return h, nil
+}
+
+// verifyChunkNodes is the number of nodes Verify reads at a time
+const verifyChunkNodes = 4096
+
+// Verify checks the map persisted by Uint32Store.WriteTo which can be read
+// from r without loading it: the header, the size, the checksum and that
+// every node only refers to nodes within the map. The error wraps
+// ErrInvalidData if the data is malformed.
+func Verify(r io.ReaderAt) error {
+	hdr := make([]byte, persistHeaderSize)
+	if err := readFullAt(r, hdr, 0); err != nil {
+		return err
+	}
+	h, err := parseHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	crc := uint32(0)
+	buf := make([]byte, verifyChunkNodes*persistNodeSize)
+	for lo := uint32(0); lo < h.nodes; lo += verifyChunkNodes {
+		n := h.nodes - lo
+		if n > verifyChunkNodes {
+			n = verifyChunkNodes
+		}
+		chunk := buf[:n*persistNodeSize]
+		if err := readFullAt(r, chunk, persistHeaderSize+int64(lo)*persistNodeSize); err != nil {
+			return err
+		}
+		crc = crc32.Update(crc, crcTable, chunk)
+		for i := uint32(0); i < n; i++ {
+			if err := checkNode(chunk[i*persistNodeSize:], lo+i, h.nodes); err != nil {
+				return err
+			}
+		}
+	}
+	if crc != h.checksum {
+		return fmt.Errorf("%w: checksum %08x want %08x", ErrInvalidData, crc, h.checksum)
+	}
+
+	end := persistHeaderSize + int64(h.nodes)*persistNodeSize
+	if n, _ := r.ReadAt(buf[:1], end); n > 0 {
+		return fmt.Errorf("%w: data after %d nodes", ErrInvalidData, h.nodes)
+	}
+	return nil
+}
+
+// checkNode checks the persisted form of node i of a map with the given number of nodes
+func checkNode(b []byte, i, nodes uint32) error {
+	if b[6]&^3 != 0 || b[7] != 0 {
+		return fmt.Errorf("%w: node %d has reserved bits set", ErrInvalidData, i)
+	}
+	if bv := getNode(b); uint64(bv.nextLo)+uint64(bv.nextLen) > uint64(nodes) {
+		return fmt.Errorf("%w: node %d refers to nodes beyond %d", ErrInvalidData, i, nodes)
+	} else if int(bv.nextOffset)+int(bv.nextLen) > 256 {
+		return fmt.Errorf("%w: node %d has next bytes beyond 255", ErrInvalidData, i)
+	}
+	return nil
+}
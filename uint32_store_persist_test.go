@@ -0,0 +1,110 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	b, err := fm.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded faststringmap.Uint32Store
+	if err := loaded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	checkLookuper(t, "loaded", &loaded, ms)
+}
+
+func TestVerify(t *testing.T) {
+	fm := faststringmap.NewUint32Store(mapSliceN(randomSmallStrings(10000, 8), 10000))
+	b, _ := fm.MarshalBinary()
+	if err := faststringmap.Verify(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	}
+
+	// fixCRC recalculates the checksum so that other checks are reached
+	fixCRC := func(b []byte) []byte {
+		binary.LittleEndian.PutUint32(b[12:], crc32.Checksum(b[16:], crc32.MakeTable(crc32.Castagnoli)))
+		return b
+	}
+	corrupt := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), b...))
+	}
+	for name, bad := range map[string][]byte{
+		"magic":     corrupt(func(b []byte) []byte { b[0] = 'X'; return b }),
+		"version":   corrupt(func(b []byte) []byte { b[4] = 99; return b }),
+		"truncated": b[:len(b)-1],
+		"trailing":  append(append([]byte(nil), b...), 0),
+		"checksum":  corrupt(func(b []byte) []byte { b[len(b)-1]++; return b }),
+		"range": corrupt(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[16:], 1<<31) // root nextLo
+			return fixCRC(b)
+		}),
+		"reserved": corrupt(func(b []byte) []byte { b[16+7] = 1; return fixCRC(b) }),
+	} {
+		err := faststringmap.Verify(bytes.NewReader(bad))
+		if !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("%s: Verify got error %v want %v", name, err, faststringmap.ErrInvalidData)
+		}
+		var m faststringmap.Uint32Store
+		if !errors.Is(m.UnmarshalBinary(bad), faststringmap.ErrInvalidData) {
+			t.Errorf("%s: UnmarshalBinary got no error", name)
+		}
+	}
+}
+
+func TestWriteToReadFrom(t *testing.T) {
+	ms := mapSliceN(randomSmallStrings(1000, 8), 500)
+	fm := faststringmap.NewUint32Store(ms)
+	var buf bytes.Buffer
+	nw, err := fm.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("next")
+	var loaded faststringmap.Uint32Store
+	nr, err := loaded.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nr != nw {
+		t.Errorf("ReadFrom read %d bytes want %d", nr, nw)
+	}
+	checkLookuper(t, "ReadFrom", &loaded, ms)
+
+	var zero faststringmap.Uint32Store
+	buf.Reset()
+	zero.WriteTo(&buf)
+	if _, err := loaded.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	checkLookuper(t, "zero ReadFrom", &loaded, mapSlice{out: []string{"", "a"}})
+}
+
+func TestMustUnmarshalUint32Store(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{"a": 1, "bc": 2})
+	b, _ := fm.MarshalBinary()
+	m := faststringmap.MustUnmarshalUint32Store(string(b))
+	if v, ok := m.LookupString("bc"); !ok || v != 2 {
+		t.Errorf("got %d, %v want 2, true", v, ok)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, faststringmap.ErrInvalidData) {
+			t.Errorf("got panic %v want %v", err, faststringmap.ErrInvalidData)
+		}
+	}()
+	faststringmap.MustUnmarshalUint32Store("FSMU")
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// LookupLongestPrefix finds the longest key in the map which is a prefix
+// of s, returning its value and length
+func (m *Uint32Store) LookupLongestPrefix(s string) (v uint32, n int, ok bool) {
+	if len(m.store) == 0 {
+		return 0, 0, false
+	}
+	bv := &m.store[m.root]
+	for i := 0; ; i++ {
+		if bv.valid {
+			v, n, ok = bv.value, i, true
+		}
+		if i == len(s) {
+			return
+		}
+		b := s[i]
+		if b < bv.nextOffset || uint16(b-bv.nextOffset) >= bv.nextLen {
+			return
+		}
+		bv = &m.store[bv.nextLo+uint32(b-bv.nextOffset)]
+	}
+}
+
+// LookupLongestPrefixBytes finds the longest key in the map which is a
+// prefix of s, returning its value and length
+func (m *Uint32Store) LookupLongestPrefixBytes(s []byte) (v uint32, n int, ok bool) {
+	if len(m.store) == 0 {
+		return 0, 0, false
+	}
+	bv := &m.store[m.root]
+	for i := 0; ; i++ {
+		if bv.valid {
+			v, n, ok = bv.value, i, true
+		}
+		if i == len(s) {
+			return
+		}
+		b := s[i]
+		if b < bv.nextOffset || uint16(b-bv.nextOffset) >= bv.nextLen {
+			return
+		}
+		bv = &m.store[bv.nextLo+uint32(b-bv.nextOffset)]
+	}
+}
+
+// LookupAllPrefixes returns every key in the map which is a prefix of s,
+// shortest first, as a Match with Start 0 and End the length of the key,
+// so that callers can choose between overlapping matches
+func (m *Uint32Store) LookupAllPrefixes(s string) []Match {
+	if len(m.store) == 0 {
+		return nil
+	}
+	var matches []Match
+	bv := &m.store[m.root]
+	for i := 0; ; i++ {
+		if bv.valid {
+			matches = append(matches, Match{End: i, Value: bv.value})
+		}
+		if i == len(s) {
+			return matches
+		}
+		b := s[i]
+		if b < bv.nextOffset || uint16(b-bv.nextOffset) >= bv.nextLen {
+			return matches
+		}
+		bv = &m.store[bv.nextLo+uint32(b-bv.nextOffset)]
+	}
+}
+
+// LookupAllPrefixesBytes returns every key in the map which is a prefix
+// of s, shortest first, as for LookupAllPrefixes
+func (m *Uint32Store) LookupAllPrefixesBytes(s []byte) []Match {
+	if len(m.store) == 0 {
+		return nil
+	}
+	var matches []Match
+	bv := &m.store[m.root]
+	for i := 0; ; i++ {
+		if bv.valid {
+			matches = append(matches, Match{End: i, Value: bv.value})
+		}
+		if i == len(s) {
+			return matches
+		}
+		b := s[i]
+		if b < bv.nextOffset || uint16(b-bv.nextOffset) >= bv.nextLen {
+			return matches
+		}
+		bv = &m.store[bv.nextLo+uint32(b-bv.nextOffset)]
+	}
+}
+
+// LookupLongestAt finds the longest key in the map which starts at
+// s[start], returning its value and the number of bytes it consumes, so
+// that a lexer can match keywords or operators at its current position.
+// It panics if start is greater than len(s).
+func (m *Uint32Store) LookupLongestAt(s string, start int) (v uint32, consumed int, ok bool) {
+	return m.LookupLongestPrefix(s[start:])
+}
+
+// LookupLongestAtBytes finds the longest key in the map which starts at
+// s[start], returning its value and the number of bytes it consumes.
+// It panics if start is greater than len(s).
+func (m *Uint32Store) LookupLongestAtBytes(s []byte, start int) (v uint32, consumed int, ok bool) {
+	return m.LookupLongestPrefixBytes(s[start:])
+}
+
+// AppendKeysWithPrefix appends the keys in the map starting with prefix
+// to a in sorted order and returns the resulting slice
+func (m *Uint32Store) AppendKeysWithPrefix(prefix string, a []string) []string {
+	m.WalkPrefixBytes(prefix, make([]byte, 0, len(prefix)+256), func(key []byte, _ uint32) bool {
+		a = append(a, string(key))
+		return true
+	})
+	return a
+}
+
+// AppendEntriesWithPrefix appends the keys in the map starting with prefix
+// to keys in sorted order, and their values to values in the same order,
+// and returns the resulting slices
+func (m *Uint32Store) AppendEntriesWithPrefix(prefix string, keys []string, values []uint32) ([]string, []uint32) {
+	m.WalkPrefixBytes(prefix, make([]byte, 0, len(prefix)+256), func(key []byte, v uint32) bool {
+		keys = append(keys, string(key))
+		values = append(values, v)
+		return true
+	})
+	return keys, values
+}
+
+// MatchIdent finds the run of bytes at the start of s for which isIdentByte
+// is true, for example an identifier in a lexer, and returns its length n
+// and whether the whole run is a key in the map, for example a keyword.
+// The trie is no longer followed once the run diverges from it.
+func (m *Uint32Store) MatchIdent(s []byte, isIdentByte func(byte) bool) (v uint32, n int, ok bool) {
+	i, inTrie := m.root, len(m.store) > 0
+	for n < len(s) && isIdentByte(s[n]) {
+		if inTrie {
+			i, inTrie = m.step(i, s[n])
+		}
+		n++
+	}
+	if !inTrie {
+		return 0, n, false
+	}
+	bv := &m.store[i]
+	return bv.value, n, bv.valid
+}
+
+// Complete returns up to n keys in the map starting with prefix in sorted
+// order, for example for autocompletion. The walk stops once n keys are
+// found, so it is fast however many keys start with prefix.
+func (m *Uint32Store) Complete(prefix string, n int) []string {
+	keys, _ := m.completeEntries(prefix, n, false)
+	return keys
+}
+
+// CompleteEntries is like Complete but also returns the values of the
+// keys in the same order
+func (m *Uint32Store) CompleteEntries(prefix string, n int) ([]string, []uint32) {
+	return m.completeEntries(prefix, n, true)
+}
+
+func (m *Uint32Store) completeEntries(prefix string, n int, withValues bool) (keys []string, values []uint32) {
+	if n <= 0 {
+		return nil, nil
+	}
+	m.WalkPrefixBytes(prefix, make([]byte, 0, len(prefix)+256), func(key []byte, v uint32) bool {
+		keys = append(keys, string(key))
+		if withValues {
+			values = append(values, v)
+		}
+		return len(keys) < n
+	})
+	return keys, values
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestLookupLongestPrefix(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"a": 1, "abc": 2, "abcde": 3, "b": 4, "": 5,
+	})
+	for _, tc := range []struct {
+		s     string
+		value uint32
+		n     int
+	}{
+		{"", 5, 0},
+		{"x", 5, 0},
+		{"a", 1, 1},
+		{"ab", 1, 1},
+		{"abc", 2, 3},
+		{"abcd", 2, 3},
+		{"abcdef", 3, 5},
+		{"bcd", 4, 1},
+	} {
+		if v, n, ok := fm.LookupLongestPrefix(tc.s); !ok || v != tc.value || n != tc.n {
+			t.Errorf("%q: got %d, %d, %v want %d, %d, true", tc.s, v, n, ok, tc.value, tc.n)
+		}
+		if v, n, ok := fm.LookupLongestPrefixBytes([]byte(tc.s)); !ok || v != tc.value || n != tc.n {
+			t.Errorf("bytes %q: got %d, %d, %v want %d, %d, true", tc.s, v, n, ok, tc.value, tc.n)
+		}
+	}
+
+	fm = faststringmap.NewUint32StoreFromMap(map[string]uint32{"ab": 1})
+	for _, s := range []string{"", "a", "b", "ac"} {
+		if v, n, ok := fm.LookupLongestPrefix(s); ok {
+			t.Errorf("%q: got %d, %d, true want not found", s, v, n)
+		}
+	}
+}
+
+func TestLookupLongestAt(t *testing.T) {
+	ops := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"<": 1, "<=": 2, "<<": 3, "<<=": 4, "=": 5, "==": 6, " ": 7,
+	})
+	src := "<<= <<<= ==="
+	var got []uint32
+	for pos := 0; pos < len(src); {
+		v, n, ok := ops.LookupLongestAt(src, pos)
+		if !ok {
+			t.Fatalf("no match at %d", pos)
+		}
+		if vb, nb, okb := ops.LookupLongestAtBytes([]byte(src), pos); vb != v || nb != n || !okb {
+			t.Errorf("bytes at %d: got %d, %d, %v want %d, %d, true", pos, vb, nb, okb, v, n)
+		}
+		got = append(got, v)
+		pos += n
+	}
+	if want := []uint32{4, 7, 3, 2, 7, 6, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if _, n, ok := ops.LookupLongestAt(src, len(src)); ok || n != 0 {
+		t.Errorf("at end: got %d, %v want 0, false", n, ok)
+	}
+}
+
+func TestAppendKeysWithPrefix(t *testing.T) {
+	m := randomSmallStrings(2000, 6)
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	for _, prefix := range []string{"", "!", "a", "ab", "~~~~~~~~~~"} {
+		var want []string
+		for k := range m {
+			if strings.HasPrefix(k, prefix) {
+				want = append(want, k)
+			}
+		}
+		sort.Strings(want)
+		got := fm.AppendKeysWithPrefix(prefix, []string{"x"})
+		if !reflect.DeepEqual(got, append([]string{"x"}, want...)) {
+			t.Errorf("%q: got %d keys want %d", prefix, len(got)-1, len(want))
+		}
+		keys, values := fm.AppendEntriesWithPrefix(prefix, nil, []uint32{99})
+		if !reflect.DeepEqual(keys, want) || len(values) != len(want)+1 || values[0] != 99 {
+			t.Fatalf("%q: entries got %d keys and %d values want %d", prefix, len(keys), len(values)-1, len(want))
+		}
+		for i, k := range keys {
+			if values[i+1] != m[k] {
+				t.Errorf("%q: value for %q got %d want %d", prefix, k, values[i+1], m[k])
+			}
+		}
+	}
+}
+
+func TestMatchIdent(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{"if": 1, "in": 2, "int": 3, "for": 4})
+	isIdent := func(b byte) bool { return b == '_' || b >= 'a' && b <= 'z' || b >= '0' && b <= '9' }
+	for _, tc := range []struct {
+		s     string
+		value uint32
+		n     int
+		ok    bool
+	}{
+		{"if x", 1, 2, true},
+		{"int(", 3, 3, true},
+		{"in", 2, 2, true},
+		{"inte", 0, 4, false},
+		{"i", 0, 1, false},
+		{"foreach x", 0, 7, false},
+		{"xyz_1 ", 0, 5, false},
+		{"(if", 0, 0, false},
+		{"", 0, 0, false},
+	} {
+		if v, n, ok := fm.MatchIdent([]byte(tc.s), isIdent); v != tc.value || n != tc.n || ok != tc.ok {
+			t.Errorf("%q: got %d, %d, %v want %d, %d, %v", tc.s, v, n, ok, tc.value, tc.n, tc.ok)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"car": 1, "card": 2, "care": 3, "careful": 4, "cart": 5, "cat": 6, "dog": 7,
+	})
+	for _, tc := range []struct {
+		prefix string
+		n      int
+		want   []string
+	}{
+		{"car", 3, []string{"car", "card", "care"}},
+		{"car", 10, []string{"car", "card", "care", "careful", "cart"}},
+		{"care", 1, []string{"care"}},
+		{"", 2, []string{"car", "card"}},
+		{"ca", 0, nil},
+		{"x", 5, nil},
+	} {
+		if got := fm.Complete(tc.prefix, tc.n); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Complete %q %d: got %q want %q", tc.prefix, tc.n, got, tc.want)
+		}
+		keys, values := fm.CompleteEntries(tc.prefix, tc.n)
+		if !reflect.DeepEqual(keys, tc.want) || len(values) != len(keys) {
+			t.Errorf("CompleteEntries %q %d: got %q %v want %q", tc.prefix, tc.n, keys, values, tc.want)
+		}
+		for i, k := range keys {
+			if v, _ := fm.LookupString(k); values[i] != v {
+				t.Errorf("CompleteEntries %q: got %d want %d", k, values[i], v)
+			}
+		}
+	}
+}
+
+func TestLookupAllPrefixes(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"": 1, "a": 2, "ab": 3, "abcd": 4, "b": 5,
+	})
+	for _, tc := range []struct {
+		s    string
+		want []faststringmap.Match
+	}{
+		{"abcde", []faststringmap.Match{{0, 0, 1}, {0, 1, 2}, {0, 2, 3}, {0, 4, 4}}},
+		{"abc", []faststringmap.Match{{0, 0, 1}, {0, 1, 2}, {0, 2, 3}}},
+		{"b", []faststringmap.Match{{0, 0, 1}, {0, 1, 5}}},
+		{"x", []faststringmap.Match{{0, 0, 1}}},
+	} {
+		if got := fm.LookupAllPrefixes(tc.s); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("LookupAllPrefixes %q: got %v want %v", tc.s, got, tc.want)
+		}
+		if got := fm.LookupAllPrefixesBytes([]byte(tc.s)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("LookupAllPrefixesBytes %q: got %v want %v", tc.s, got, tc.want)
+		}
+	}
+	fm = faststringmap.NewUint32StoreFromMap(map[string]uint32{"abc": 1})
+	if got := fm.LookupAllPrefixes("ab"); got != nil {
+		t.Errorf("no prefix: got %v", got)
+	}
+	var zero faststringmap.Uint32Store
+	if got := zero.LookupAllPrefixes("a"); got != nil {
+		t.Errorf("zero: got %v", got)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// BuildProgress reports how far the construction of a Uint32Store has got
+	BuildProgress struct {
+		Keys      int           // keys added to the trie so far
+		TotalKeys int           // keys in the map being built
+		Nodes     int           // trie nodes allocated so far
+		Elapsed   time.Duration // time since the build started
+	}
+
+	// buildReporter passes progress from one or more uint32Builders to a
+	// progress function, calling it at most once every so many keys
+	buildReporter struct {
+		fn    func(BuildProgress)
+		every int
+		start time.Time
+		mu    sync.Mutex
+		p     BuildProgress
+		next  int // keys at which to next call fn
+	}
+)
+
+// SetProgress arranges for Build and BuildParallel to call fn each time
+// about another every keys have been added to the trie, and once when the
+// build is complete, so that progress can be shown or slow builds logged.
+// If every is less than 1 then fn is only called when the build is
+// complete. fn is never called concurrently with itself. SetProgress must
+// not be called concurrently with a build.
+func (b *Uint32StoreBuilder) SetProgress(every int, fn func(BuildProgress)) {
+	b.progress, b.progressEvery = fn, every
+}
+
+// newReporter returns a buildReporter for a build of totalKeys keys, or
+// nil if no progress function has been set
+func (b *Uint32StoreBuilder) newReporter(start time.Time, totalKeys int) *buildReporter {
+	if b.progress == nil {
+		return nil
+	}
+	every := b.progressEvery
+	if every < 1 {
+		every = totalKeys + 1
+	}
+	return &buildReporter{
+		fn:    b.progress,
+		every: every,
+		start: start,
+		p:     BuildProgress{TotalKeys: totalKeys},
+		next:  every,
+	}
+}
+
+// reportKey records that a key has been added to the trie, passing the
+// progress so far to the reporter every so many keys
+func (b *uint32Builder) reportKey() {
+	b.keysIn++
+	if b.keysIn >= b.report.every {
+		b.report.add(b.keysIn, b.len-b.lenIn)
+		b.keysIn, b.lenIn = 0, b.len
+	}
+}
+
+// add adds keys and nodes to the progress, calling the progress function
+// if enough keys have been added since it was last called, unless all the
+// keys have been added as then finish will call it
+func (r *buildReporter) add(keys, nodes int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.p.Keys += keys
+	r.p.Nodes += nodes
+	if r.p.Keys >= r.next && r.p.Keys < r.p.TotalKeys {
+		r.next = r.p.Keys + r.every
+		r.call()
+	}
+}
+
+// finish calls the progress function for the completed map of nodes nodes
+func (r *buildReporter) finish(nodes int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.p.Keys, r.p.Nodes = r.p.TotalKeys, nodes
+	r.call()
+}
+
+func (r *buildReporter) call() {
+	r.p.Elapsed = time.Since(r.start)
+	r.fn(r.p)
+}
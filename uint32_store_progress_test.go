@@ -0,0 +1,53 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestUint32StoreBuilderProgress(t *testing.T) {
+	m := randomSmallStrings(4096, 8)
+	var b faststringmap.Uint32StoreBuilder
+	for k, v := range m {
+		b.Add(k, v)
+	}
+	build := map[string]func() (faststringmap.Uint32Store, error){
+		"Build":         b.Build,
+		"BuildParallel": func() (faststringmap.Uint32Store, error) { return b.BuildParallel(4) },
+	}
+	for name, build := range build {
+		var reports []faststringmap.BuildProgress
+		b.SetProgress(1000, func(p faststringmap.BuildProgress) { reports = append(reports, p) })
+		fm, err := build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(reports) < 2 {
+			t.Fatalf("%s: got %d reports want at least 2", name, len(reports))
+		}
+		for i, p := range reports {
+			if p.TotalKeys != len(m) {
+				t.Errorf("%s: report %d: TotalKeys got %d want %d", name, i, p.TotalKeys, len(m))
+			}
+			if i > 0 && (p.Keys < reports[i-1].Keys || p.Nodes < reports[i-1].Nodes) {
+				t.Errorf("%s: report %d: progress went backwards %+v after %+v", name, i, p, reports[i-1])
+			}
+		}
+		last := reports[len(reports)-1]
+		if last.Keys != len(m) || last.Nodes != fm.MemStats().Nodes {
+			t.Errorf("%s: last report got %+v want %d keys and %d nodes", name, last, len(m), fm.MemStats().Nodes)
+		}
+	}
+
+	var reports int
+	b.SetProgress(0, func(faststringmap.BuildProgress) { reports++ })
+	if _, err := b.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if reports != 1 {
+		t.Errorf("got %d reports want 1", reports)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// Range calls fn for each key in the map with lo <= key < hi in sorted
+// order until fn returns false. Only the nodes on the path to lo and
+// those for keys in the range, and for the first key after it, are
+// visited, so scanning a small window of a large map is fast.
+func (m *Uint32Store) Range(lo, hi string, fn func(string, uint32) bool) {
+	if len(m.store) == 0 || lo >= hi {
+		return
+	}
+	var frames [walkStackSize]iterFrame
+	it := Uint32StoreIterator{m: m, stack: frames[:0], key: make([]byte, 0, 256)}
+	it.Seek(lo)
+	for it.Next() && string(it.key) < hi {
+		if !fn(string(it.key), it.value) {
+			return
+		}
+	}
+}
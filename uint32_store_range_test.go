@@ -0,0 +1,57 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestRange(t *testing.T) {
+	m := randomSmallStrings(3000, 5)
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	sorted := make([]string, 0, len(m))
+	for k := range m {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	bounds := []string{"", "!", "a", "ab", "abc", "b", "m~", "~", "~~~~~~", "\xff", sorted[10], sorted[100], sorted[100] + "\x00", sorted[2000][:1]}
+	for _, lo := range bounds {
+		for _, hi := range bounds {
+			var want []string
+			for _, k := range sorted {
+				if lo <= k && k < hi {
+					want = append(want, k)
+				}
+			}
+			var got []string
+			fm.Range(lo, hi, func(k string, v uint32) bool {
+				if v != m[k] {
+					t.Errorf("%q: got %d want %d", k, v, m[k])
+				}
+				got = append(got, k)
+				return true
+			})
+			if !equalStrings(got, want) {
+				t.Errorf("Range %q %q: got %d keys want %d", lo, hi, len(got), len(want))
+			}
+		}
+	}
+
+	n := 0
+	fm.Range("", "~", func(string, uint32) bool {
+		n++
+		return n < 5
+	})
+	if n != 5 {
+		t.Errorf("stopped after %d keys want 5", n)
+	}
+	var zero faststringmap.Uint32Store
+	zero.Range("", "z", func(k string, _ uint32) bool {
+		t.Errorf("zero map has key %q", k)
+		return true
+	})
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "io"
+
+// LookupReader looks up the key made up of the bytes read from r up to
+// io.EOF, without collecting them in a string or byte slice. All the bytes
+// are read even once the key is known not to be in the map, so r is
+// always left at its end. Errors from r other than io.EOF are returned.
+func (m *Uint32Store) LookupReader(r io.ByteReader) (uint32, bool, error) {
+	i, inTrie := m.root, len(m.store) > 0
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		if inTrie {
+			i, inTrie = m.step(i, b)
+		}
+	}
+	if !inTrie {
+		return 0, false, nil
+	}
+	bv := &m.store[i]
+	return bv.value, bv.valid, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestLookupReader(t *testing.T) {
+	m := randomSmallStrings(1000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	for _, k := range ms.in {
+		r := strings.NewReader(k)
+		if v, ok, err := fm.LookupReader(r); err != nil || !ok || v != m[k] {
+			t.Errorf("%q: got %d, %v, %v want %d, true, nil", k, v, ok, err, m[k])
+		}
+	}
+	for _, k := range ms.out {
+		r := strings.NewReader(k + "\x00\x00")
+		if v, ok, err := fm.LookupReader(r); err != nil || ok {
+			t.Errorf("%q: got %d, %v, %v want not found", k, v, ok, err)
+		}
+		if r.Len() != 0 {
+			t.Errorf("%q: %d bytes left unread", k, r.Len())
+		}
+	}
+
+	bad := errors.New("bad")
+	if _, _, err := fm.LookupReader(bufio.NewReader(iotest.ErrReader(bad))); err != bad {
+		t.Errorf("got error %v want %v", err, bad)
+	}
+	var zero faststringmap.Uint32Store
+	if _, ok, err := zero.LookupReader(bytes.NewReader(nil)); ok || err != nil {
+		t.Errorf("zero got %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Thresholds above which Report makes recommendations
+const (
+	reportWastedFraction = 0.5  // fraction of nodes which are unused slots
+	reportChainFraction  = 0.5  // fraction of nodes in single child chains
+	reportMaxDepth       = 64   // key length in bytes
+	reportSparseRange    = 16   // minimum range length to count as sparse
+	reportSparseFill     = 0.25 // maximum fraction of range used to count as sparse
+)
+
+// Report describes the shape of the trie underlying a map, to help
+// understand its memory use and lookup performance
+type Report struct {
+	Keys         int // number of keys
+	Nodes        int // number of byteValues in the store
+	UnusedNodes  int // byteValues which are neither a key nor lead to a key
+	SparseRanges int // ranges of next bytes which are mostly unused
+	ChainNodes   int // byteValues which are not keys and have a single next byte
+	LongestChain int // longest run of ChainNodes
+	MaxDepth     int // length of the longest key
+
+	// Recommendations for improving on pathological shapes
+	Recommendations []string
+}
+
+// Report analyses the trie underlying the map
+func (m *Uint32Store) Report() Report {
+	var r Report
+	r.Nodes = len(m.store)
+	if r.Nodes > 0 {
+		m.reportFrom(&r, m.root)
+	}
+
+	if r.Nodes > 0 && float64(r.UnusedNodes) > reportWastedFraction*float64(r.Nodes) {
+		r.Recommendations = append(r.Recommendations, fmt.Sprintf(
+			"%d of %d nodes are unused slots in %d sparse ranges: keys with widely spread bytes would benefit from bitmap or adaptive nodes, or from remapping the bytes used to a compact range",
+			r.UnusedNodes, r.Nodes, r.SparseRanges))
+	}
+	if r.Nodes > 0 && float64(r.ChainNodes) > reportChainFraction*float64(r.Nodes) {
+		r.Recommendations = append(r.Recommendations, fmt.Sprintf(
+			"%d of %d nodes are in single child chains of up to %d bytes: long keys with little branching would benefit from path compression",
+			r.ChainNodes, r.Nodes, r.LongestChain))
+	}
+	if r.MaxDepth > reportMaxDepth {
+		r.Recommendations = append(r.Recommendations, fmt.Sprintf(
+			"the longest key is %d bytes: lookups visit one node per byte so a hash based map may be faster for long keys",
+			r.MaxDepth))
+	}
+	return r
+}
+
+// reportFrame is a node being visited by reportFrom
+type reportFrame struct {
+	sumFrame     // sum is the number of next nodes visited which lead to keys
+	chain    int // length of the chain of single child nodes leading to the next nodes
+}
+
+// reportFrom accumulates statistics for the sub-trie rooted at store index
+// i, using an explicit stack like walkNodesFrom. It reports whether the
+// sub-trie contains any keys.
+func (m *Uint32Store) reportFrom(r *Report, i uint32) bool {
+	var frames [walkStackSize]reportFrame
+	stack := append(frames[:0], reportFrame{sumFrame{node: i}, r.enter(&m.store[i], 0, 0)})
+	for {
+		f := &stack[len(stack)-1]
+		bv := &m.store[f.node]
+		if f.next < int(bv.nextLen) {
+			c := bv.nextLo + uint32(f.next)
+			f.next++
+			chain := r.enter(&m.store[c], len(stack), f.chain)
+			stack = append(stack, reportFrame{sumFrame{node: c}, chain})
+			continue
+		}
+		used := int(f.sum)
+		if n := int(bv.nextLen); n >= reportSparseRange && float64(used) < reportSparseFill*float64(n) {
+			r.SparseRanges++
+		}
+		hasKeys := bv.valid || used > 0
+		if stack = stack[:len(stack)-1]; len(stack) == 0 {
+			return hasKeys
+		}
+		if hasKeys {
+			stack[len(stack)-1].sum++
+		} else {
+			r.UnusedNodes++
+		}
+	}
+}
+
+// enter accumulates the statistics for bv at depth bytes, where chain is
+// the length of the chain of single child nodes leading to it, and returns
+// the length of the chain leading to its next nodes
+func (r *Report) enter(bv *byteValue, depth, chain int) int {
+	if bv.valid {
+		r.Keys++
+		if depth > r.MaxDepth {
+			r.MaxDepth = depth
+		}
+	}
+	if bv.valid || bv.nextLen != 1 {
+		return 0
+	}
+	r.ChainNodes++
+	if chain++; chain > r.LongestChain {
+		r.LongestChain = chain
+	}
+	return chain
+}
+
+// String returns the report in a readable form
+func (r Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "keys: %d, nodes: %d, unused nodes: %d, sparse ranges: %d, chain nodes: %d, longest chain: %d, max depth: %d",
+		r.Keys, r.Nodes, r.UnusedNodes, r.SparseRanges, r.ChainNodes, r.LongestChain, r.MaxDepth)
+	for _, rec := range r.Recommendations {
+		sb.WriteString("\n* ")
+		sb.WriteString(rec)
+	}
+	return sb.String()
+}
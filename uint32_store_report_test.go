@@ -0,0 +1,60 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestReport(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		m    map[string]uint32
+		want faststringmap.Report
+		recs []string
+	}{
+		{
+			name: "empty",
+			m:    nil,
+			want: faststringmap.Report{Nodes: 1},
+		},
+		{
+			name: "digits",
+			m:    map[string]uint32{"1": 1, "2": 2, "3": 3, "12": 4},
+			want: faststringmap.Report{Keys: 4, Nodes: 5, MaxDepth: 2},
+		},
+		{
+			name: "sparse",
+			m:    map[string]uint32{"a!": 1, "a~": 2},
+			want: faststringmap.Report{Keys: 2, Nodes: 96, UnusedNodes: 92, SparseRanges: 1, ChainNodes: 1, LongestChain: 1, MaxDepth: 2},
+			recs: []string{"bitmap"},
+		},
+		{
+			name: "chain",
+			m:    map[string]uint32{strings.Repeat("x", 100): 1},
+			want: faststringmap.Report{Keys: 1, Nodes: 101, ChainNodes: 100, LongestChain: 100, MaxDepth: 100},
+			recs: []string{"path compression", "hash"},
+		},
+	} {
+		fm := faststringmap.NewUint32StoreFromMap(tc.m)
+		got := fm.Report()
+		recs := got.Recommendations
+		got.Recommendations = nil
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %+v want %+v", tc.name, got, tc.want)
+		}
+		if len(recs) != len(tc.recs) {
+			t.Errorf("%s: got recommendations %q", tc.name, recs)
+			continue
+		}
+		for i, rec := range recs {
+			if !strings.Contains(rec, tc.recs[i]) {
+				t.Errorf("%s: recommendation %q does not mention %q", tc.name, rec, tc.recs[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sort"
+
+// BuildInto creates the map from the data supplied in src like
+// NewUint32Store and stores it in *dst, reusing the memory held by *dst if
+// it is enough for the new map, so that a service rebuilding a map of much
+// the same size from time to time does not allocate a new one each time.
+// The blocks used during construction are reused from build to build
+// anyway, see WarmBuildPool. As the old map is overwritten, neither *dst
+// nor any copy of it sharing its store, including the maps it was made
+// from or has made by WithKey and WithoutKey, may be in use by anything else.
+func BuildInto(dst *Uint32Store, src Uint32Source) {
+	keys := src.AppendKeys([]string(nil))
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		store := dst.store[:0]
+		*dst = Uint32Store{store: append(store, byteValue{})}
+		return
+	}
+	b := uint32Builder{
+		keys:  keys,
+		value: func(i int) uint32 { return src.Get(keys[i]) },
+		reuse: dst.store,
+	}
+	*dst = Uint32Store{store: b.build(), n: len(keys)}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestBuildInto(t *testing.T) {
+	m := randomSmallStrings(2000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	var fm faststringmap.Uint32Store
+	faststringmap.BuildInto(&fm, ms)
+	checkLookuper(t, "first", &fm, ms)
+	size := fm.SizeInBytes()
+
+	// a smaller map reuses the store
+	small := mapSliceN(randomSmallStrings(100, 8), 50)
+	faststringmap.BuildInto(&fm, small)
+	checkLookuper(t, "smaller", &fm, small)
+	if fm.SizeInBytes() != size {
+		t.Errorf("smaller map holds %d bytes want reused %d", fm.SizeInBytes(), size)
+	}
+	if want := faststringmap.NewUint32Store(small); fm.Fingerprint() != want.Fingerprint() {
+		t.Error("smaller map differs from NewUint32Store")
+	}
+
+	// a larger map needs a new store
+	faststringmap.BuildInto(&fm, ms)
+	checkLookuper(t, "larger", &fm, ms)
+	faststringmap.BuildInto(&fm, mapSliceN(randomSmallStrings(4000, 8), 2000))
+	if fm.SizeInBytes() <= size {
+		t.Errorf("larger map holds %d bytes want more than %d", fm.SizeInBytes(), size)
+	}
+	size = fm.SizeInBytes()
+
+	faststringmap.BuildInto(&fm, faststringmap.Uint32MapSource{})
+	if fm.Len() != 0 || fm.SizeInBytes() != size {
+		t.Errorf("empty map has %d keys holding %d bytes want 0 holding %d", fm.Len(), fm.SizeInBytes(), size)
+	}
+	if v, ok := fm.LookupString(""); ok {
+		t.Errorf("empty string present in empty map with value %d", v)
+	}
+}
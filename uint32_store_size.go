@@ -0,0 +1,66 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "unsafe"
+
+// MemStats is a breakdown of the memory used by a map
+type MemStats struct {
+	Keys        int // number of keys
+	Nodes       int // number of byteValues in the store
+	NodeBytes   int // bytes per byteValue
+	StoreBytes  int // bytes held by the store, including spare capacity
+	WastedNodes int // byteValues which are neither a key nor lead to a key
+	WastedBytes int // bytes of WastedNodes and spare capacity
+}
+
+// SizeInBytes returns the number of bytes of memory held by the map,
+// excluding the Uint32Store value itself
+func (m *Uint32Store) SizeInBytes() int {
+	return cap(m.store) * int(unsafe.Sizeof(byteValue{}))
+}
+
+// MemStats returns a breakdown of the memory used by the map. Wasted nodes
+// are the unused slots in ranges of next bytes, which are most of the nodes
+// when the bytes of keys are widely spread, and ranges left unused by WithKey.
+func (m *Uint32Store) MemStats() MemStats {
+	nodeBytes := int(unsafe.Sizeof(byteValue{}))
+	used := 0
+	if len(m.store) > 0 {
+		used, _ = m.usedFrom(m.root)
+	}
+	wasted := len(m.store) - used
+	return MemStats{
+		Keys:        m.Len(),
+		Nodes:       len(m.store),
+		NodeBytes:   nodeBytes,
+		StoreBytes:  m.SizeInBytes(),
+		WastedNodes: wasted,
+		WastedBytes: (cap(m.store) - used) * nodeBytes,
+	}
+}
+
+// usedFrom returns the number of byteValues in the sub-trie rooted at
+// store index i which are a key or lead to a key, and whether there are any
+func (m *Uint32Store) usedFrom(i uint32) (int, bool) {
+	var frames [walkStackSize]sumFrame
+	stack := append(frames[:0], sumFrame{node: i})
+	for {
+		f := &stack[len(stack)-1]
+		bv := &m.store[f.node]
+		if f.next < int(bv.nextLen) {
+			c := bv.nextLo + uint32(f.next)
+			f.next++
+			stack = append(stack, sumFrame{node: c})
+			continue
+		}
+		n := f.sum
+		if bv.valid || n > 0 {
+			n++ // the node itself
+		}
+		if stack = stack[:len(stack)-1]; len(stack) == 0 {
+			return int(n), n > 0
+		}
+		stack[len(stack)-1].sum += n
+	}
+}
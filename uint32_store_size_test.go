@@ -0,0 +1,44 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestMemStats(t *testing.T) {
+	// root with a range of 26 next bytes of which 2 are used
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{"a": 1, "z": 2})
+	want := faststringmap.MemStats{
+		Keys:        2,
+		Nodes:       27,
+		NodeBytes:   12,
+		StoreBytes:  27 * 12,
+		WastedNodes: 24,
+		WastedBytes: 24 * 12,
+	}
+	if got := fm.MemStats(); got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if got := fm.SizeInBytes(); got != want.StoreBytes {
+		t.Errorf("SizeInBytes got %d want %d", got, want.StoreBytes)
+	}
+
+	// adding "0" copies the root with a wider range, leaving the old root
+	// and range unused
+	fm = fm.WithKey("0", 3)
+	st := fm.MemStats()
+	if st.Keys != 3 || st.Nodes != 27+75+1 || st.WastedNodes != st.Nodes-4 {
+		t.Errorf("after WithKey got %+v", st)
+	}
+	if st.WastedBytes < st.WastedNodes*12 || st.StoreBytes != fm.SizeInBytes() {
+		t.Errorf("after WithKey bytes got %+v", st)
+	}
+
+	var zero faststringmap.Uint32Store
+	if got := zero.MemStats(); got != (faststringmap.MemStats{NodeBytes: 12}) {
+		t.Errorf("zero got %+v", got)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotSorted is returned (wrapped) when keys are not in increasing order
+var ErrNotSorted = errors.New("faststringmap: keys not sorted")
+
+// NewUint32StoreFromSortedKeys creates from keys, which must be in
+// increasing order with no duplicates, with get giving the value for each
+// key. Unlike NewUint32Store the keys are neither copied nor sorted, which
+// saves most of the build time for large sets of keys already in order.
+// The keys are trusted: if they might not be in order check them first with
+// CheckSortedKeys, otherwise the map is undefined and creating it may panic.
+func NewUint32StoreFromSortedKeys(keys []string, get func(string) uint32) Uint32Store {
+	return newUint32StoreSorted(keys, func(i int) uint32 { return get(keys[i]) })
+}
+
+// CheckSortedKeys checks that keys are in increasing order with no
+// duplicates, as required by NewUint32StoreFromSortedKeys. The error wraps
+// ErrDuplicateKey or ErrNotSorted and gives the first key out of order.
+func CheckSortedKeys(keys []string) error {
+	for i := 1; i < len(keys); i++ {
+		if k := keys[i]; k <= keys[i-1] {
+			if k == keys[i-1] {
+				return fmt.Errorf("%w: %q", ErrDuplicateKey, k)
+			}
+			return fmt.Errorf("%w: %q at %d follows %q", ErrNotSorted, k, i, keys[i-1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestNewUint32StoreFromSortedKeys(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	keys := append([]string(nil), ms.in...)
+	sort.Strings(keys)
+	if err := faststringmap.CheckSortedKeys(keys); err != nil {
+		t.Fatal(err)
+	}
+	fm := faststringmap.NewUint32StoreFromSortedKeys(keys, func(k string) uint32 { return m[k] })
+	checkLookuper(t, "sorted", &fm, ms)
+
+	fm = faststringmap.NewUint32StoreFromSortedKeys(nil, nil)
+	checkLookuper(t, "empty", &fm, mapSlice{out: []string{"", "a"}})
+}
+
+func TestCheckSortedKeys(t *testing.T) {
+	for _, tc := range []struct {
+		keys []string
+		err  error
+	}{
+		{nil, nil},
+		{[]string{""}, nil},
+		{[]string{"", "a", "ab", "b"}, nil},
+		{[]string{"a", "b", "b"}, faststringmap.ErrDuplicateKey},
+		{[]string{"a", "c", "b"}, faststringmap.ErrNotSorted},
+		{[]string{"ab", "a"}, faststringmap.ErrNotSorted},
+	} {
+		if err := faststringmap.CheckSortedKeys(tc.keys); !errors.Is(err, tc.err) || (err == nil) != (tc.err == nil) {
+			t.Errorf("%q: got error %v want %v", tc.keys, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// maxStreamLine is the longest line NewUint32StoreFromSortedReader accepts
+const maxStreamLine = 64 << 20
+
+// NewUint32StoreFromSortedReader creates from the lines read from r, which
+// parse turns into a key and value. The keys must be in increasing byte
+// order with no duplicates, as given by LC_ALL=C sort, and the trie is
+// built as the lines are read, so the keys are never all held in memory.
+// The key returned by parse may refer to line, which is only valid until
+// parse returns. Errors from parse are returned with the line number, and
+// keys out of order give an error wrapping ErrNotSorted or ErrDuplicateKey.
+func NewUint32StoreFromSortedReader(r io.Reader, parse func(line []byte) (key []byte, v uint32, err error)) (Uint32Store, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxStreamLine)
+	var sb sortedBuilder
+	for line := 1; sc.Scan(); line++ {
+		k, v, err := parse(sc.Bytes())
+		if err == nil {
+			err = sb.add(k, v)
+		}
+		if err != nil {
+			return Uint32Store{}, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Uint32Store{}, err
+	}
+	return sb.finish(), nil
+}
+
+type (
+	// sortedBuilder builds a Uint32Store from keys added in sorted order.
+	// Only the nodes on the path to the last key added are open, and the
+	// next nodes of a node are added to the store when it is closed, once
+	// a key not starting with its byte sequence is added. The store holds
+	// the root first, then ranges of next nodes in the order they close.
+	sortedBuilder struct {
+		store []byteValue // store[0] is set to the root by finish
+		open  []openNode  // open[d] is the node for last[:d]
+		last  []byte      // last key added
+		n     int         // number of keys added
+	}
+
+	// openNode is a node which may have more next nodes added
+	openNode struct {
+		bv    byteValue
+		next  []byteValue // closed next nodes so far
+		bytes []byte      // byte leading to each of next
+	}
+)
+
+// add adds key k, which must be greater than any added before, with value v
+func (sb *sortedBuilder) add(k []byte, v uint32) error {
+	if sb.n == 0 {
+		sb.store = append(sb.store[:0], byteValue{}) // room for the root
+		sb.open = append(sb.open[:0], openNode{})
+	} else if c := bytes.Compare(k, sb.last); c <= 0 {
+		if c == 0 {
+			return fmt.Errorf("%w: %q", ErrDuplicateKey, k)
+		}
+		return fmt.Errorf("%w: %q follows %q", ErrNotSorted, k, sb.last)
+	}
+	p := 0
+	for p < len(k) && p < len(sb.last) && k[p] == sb.last[p] {
+		p++
+	}
+	sb.closeTo(p)
+	for d := p; d < len(k); d++ {
+		sb.push()
+	}
+	top := &sb.open[len(sb.open)-1].bv
+	top.valid, top.value = true, v
+	sb.last = append(sb.last[:0], k...)
+	sb.n++
+	return nil
+}
+
+// push opens a new node at the end of the path, reusing old buffers
+func (sb *sortedBuilder) push() {
+	if len(sb.open) < cap(sb.open) {
+		sb.open = sb.open[:len(sb.open)+1]
+		o := &sb.open[len(sb.open)-1]
+		o.bv, o.next, o.bytes = byteValue{}, o.next[:0], o.bytes[:0]
+		return
+	}
+	sb.open = append(sb.open, openNode{})
+}
+
+// closeTo closes the open nodes for last[:d] for every d greater than depth
+func (sb *sortedBuilder) closeTo(depth int) {
+	for d := len(sb.open) - 1; d > depth; d-- {
+		parent := &sb.open[d-1]
+		parent.next = append(parent.next, sb.close(&sb.open[d]))
+		parent.bytes = append(parent.bytes, sb.last[d-1])
+	}
+	sb.open = sb.open[:depth+1]
+}
+
+// close adds the range of next nodes of o to the store and returns o
+func (sb *sortedBuilder) close(o *openNode) byteValue {
+	bv := o.bv
+	if len(o.next) == 0 {
+		return bv
+	}
+	lo, hi := o.bytes[0], o.bytes[len(o.bytes)-1]
+	bv.nextLo, bv.nextLen, bv.nextOffset = uint32(len(sb.store)), uint16(hi-lo)+1, lo
+	sb.store = append(sb.store, make([]byteValue, bv.nextLen)...)
+	for j, next := range o.next {
+		sb.store[bv.nextLo+uint32(o.bytes[j]-lo)] = next
+	}
+	return bv
+}
+
+// finish closes every open node and returns the map
+func (sb *sortedBuilder) finish() Uint32Store {
+	if sb.n == 0 {
+		return Uint32Store{store: []byteValue{{}}}
+	}
+	sb.closeTo(0)
+	sb.store[0] = sb.close(&sb.open[0])
+	store := make([]byteValue, len(sb.store))
+	copy(store, sb.store)
+	return Uint32Store{store: store, n: sb.n}
+}
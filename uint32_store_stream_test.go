@@ -0,0 +1,77 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func parseTabLine(line []byte) ([]byte, uint32, error) {
+	i := bytes.IndexByte(line, '\t')
+	if i < 0 {
+		return nil, 0, errors.New("no tab")
+	}
+	v, err := strconv.ParseUint(string(line[i+1:]), 10, 32)
+	return line[:i], uint32(v), err
+}
+
+func TestNewUint32StoreFromSortedReader(t *testing.T) {
+	m := randomSmallStrings(5000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	keys := make([]string, 0, len(ms.in))
+	for _, k := range ms.in {
+		if !strings.ContainsAny(k, "\t\n") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k + "\t" + strconv.Itoa(int(m[k])) + "\n")
+	}
+	fm, err := faststringmap.NewUint32StoreFromSortedReader(strings.NewReader(sb.String()), parseTabLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms.in = keys
+	checkLookuper(t, "stream", &fm, ms)
+	want := faststringmap.NewUint32Store(ms)
+	if fm.Fingerprint() != want.Fingerprint() {
+		t.Error("streamed map differs from NewUint32Store")
+	}
+	if got, want := fm.MemStats().Nodes, want.MemStats().Nodes; got != want {
+		t.Errorf("streamed map has %d nodes want %d", got, want)
+	}
+
+	for _, tc := range []struct {
+		input string
+		err   error
+	}{
+		{"a\t1\nb\t2\na\t3\n", faststringmap.ErrNotSorted},
+		{"a\t1\nab\t2\nab\t3\n", faststringmap.ErrDuplicateKey},
+	} {
+		_, err := faststringmap.NewUint32StoreFromSortedReader(strings.NewReader(tc.input), parseTabLine)
+		if !errors.Is(err, tc.err) || !strings.HasPrefix(err.Error(), "line 3: ") {
+			t.Errorf("%q: got %v want line 3: %v", tc.input, err, tc.err)
+		}
+	}
+	if _, err := faststringmap.NewUint32StoreFromSortedReader(strings.NewReader("a\t1\nb\n"), parseTabLine); err == nil || err.Error() != "line 2: no tab" {
+		t.Errorf("parse error: got %v", err)
+	}
+
+	fm, err = faststringmap.NewUint32StoreFromSortedReader(strings.NewReader(""), parseTabLine)
+	if err != nil || fm.Len() != 0 {
+		t.Errorf("empty: got Len %d, %v", fm.Len(), err)
+	}
+	fm, err = faststringmap.NewUint32StoreFromSortedReader(strings.NewReader("\t7\n"), parseTabLine)
+	if v, ok := fm.LookupString(""); err != nil || !ok || v != 7 {
+		t.Errorf("empty key: got %d, %v, %v", v, ok, err)
+	}
+}
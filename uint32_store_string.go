@@ -0,0 +1,57 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+// goStringMaxKeys is the largest map for which GoString gives the contents
+const goStringMaxKeys = 32
+
+// String returns a summary of the map giving the number of keys, the
+// number of nodes in the store and the memory used by the store
+func (m *Uint32Store) String() string {
+	return fmt.Sprintf("Uint32Store{keys: %d, nodes: %d, bytes: %d}",
+		m.Len(), len(m.store), uintptr(len(m.store))*unsafe.Sizeof(byteValue{}))
+}
+
+// Format prints the map the same way whether it is a Uint32Store or a
+// pointer to one. %+v gives the nodes of the store and the number of keys,
+// with the index of the root if it is not the first node, %#v is the same
+// as GoString and other verbs are the same as String.
+func (m Uint32Store) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		io.WriteString(f, m.GoString())
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "{store:%+v n:%d", m.store, m.n)
+		if m.root != 0 {
+			fmt.Fprintf(f, " root:%d", m.root)
+		}
+		io.WriteString(f, "}")
+	default:
+		io.WriteString(f, m.String())
+	}
+}
+
+// GoString returns Go syntax which creates the map when it has only a few keys
+// and otherwise an expression for an empty map with a comment summarising it
+func (m *Uint32Store) GoString() string {
+	if n := m.Len(); n > goStringMaxKeys {
+		return fmt.Sprintf("faststringmap.Uint32Store{ /* %d keys, %d nodes */ }", n, len(m.store))
+	}
+	var sb strings.Builder
+	sb.WriteString("faststringmap.NewUint32StoreFromMap(map[string]uint32{")
+	sep := ""
+	m.walk(func(key []byte, v uint32) bool {
+		fmt.Fprintf(&sb, "%s%q: %d", sep, key, v)
+		sep = ", "
+		return true
+	})
+	sb.WriteString("})")
+	return sb.String()
+}
@@ -0,0 +1,38 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestUint32StoreString(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{"key1": 42, "key2": 27644437, "l": 2})
+	if got, want := fmt.Sprint(&fm), "Uint32Store{keys: 3, nodes: 7, bytes: 84}"; got != want {
+		t.Errorf("String got %s want %s", got, want)
+	}
+	if got, want := fmt.Sprintf("%#v", &fm),
+		`faststringmap.NewUint32StoreFromMap(map[string]uint32{"key1": 42, "key2": 27644437, "l": 2})`; got != want {
+		t.Errorf("GoString got %s want %s", got, want)
+	}
+
+	big := faststringmap.NewUint32StoreFromMap(randomSmallStrings(100, 4))
+	if got := fmt.Sprintf("%#v", &big); !strings.Contains(got, "100 keys") {
+		t.Errorf("GoString got %s", got)
+	}
+
+	if got, want := fmt.Sprint(fm), fmt.Sprint(&fm); got != want {
+		t.Errorf("String of value got %s want %s", got, want)
+	}
+	if got, want := fmt.Sprintf("%#v", fm), fmt.Sprintf("%#v", &fm); got != want {
+		t.Errorf("GoString of value got %s want %s", got, want)
+	}
+	derived := fm.WithKey("m", 4)
+	if got := fmt.Sprintf("%+v", derived); !strings.HasSuffix(got, " n:4 root:10}") {
+		t.Errorf("%%+v after WithKey got %s", got)
+	}
+}
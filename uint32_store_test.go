@@ -23,12 +23,66 @@ func TestFastStringToUint32BigSpan(t *testing.T) {
 	checkWithMapSlice(t, ms)
 }
 
+func TestFastStringToUint32AllBytes(t *testing.T) {
+	m := make(map[string]uint32, 512)
+	for i := 0; i < 256; i++ {
+		m[string([]byte{byte(i)})] = uint32(i)
+		m[string([]byte{'x', byte(i)})] = uint32(i + 256)
+	}
+	checkWithMapSlice(t, mapSliceN(m, len(m)))
+
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	b, _ := fm.MarshalBinary()
+	var loaded faststringmap.Uint32Store
+	if err := loaded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range m {
+		if v, ok := loaded.LookupString(k); !ok || v != want {
+			t.Errorf("loaded %q: got %d, %v want %d, true", k, v, ok, want)
+		}
+	}
+}
+
 func TestFastStringToUint32(t *testing.T) {
 	const nStrs = 8192
 	m := randomSmallStrings(nStrs, 8)
 	checkWithMapSlice(t, mapSliceN(m, len(m)/2))
 }
 
+func TestFastStringToUint32LongKeys(t *testing.T) {
+	long := strings.Repeat("/very/long/path", 1<<14)
+	m := map[string]uint32{long: 1, long + "a": 2, long + "b": 3, long[:1<<17]: 4, "x": 5}
+	checkWithMapSlice(t, mapSliceN(m, 3))
+}
+
+func TestLookupJoin(t *testing.T) {
+	fm := faststringmap.NewUint32Store(mapSliceN(map[string]uint32{
+		"a/b/c": 1, "a/b": 2, "a//c": 3, "a": 4, "": 5, "x/y": 6}, 6))
+	for _, tc := range []struct {
+		parts []string
+		value uint32
+		ok    bool
+	}{
+		{[]string{"a", "b", "c"}, 1, true},
+		{[]string{"a/b", "c"}, 1, true},
+		{[]string{"a", "b"}, 2, true},
+		{[]string{"a", "", "c"}, 3, true},
+		{[]string{"a"}, 4, true},
+		{[]string{}, 5, true},
+		{[]string{""}, 5, true},
+		{[]string{"x", "y"}, 6, true},
+		{[]string{"a", "c"}, 0, false},
+		{[]string{"a", "b", "c", ""}, 0, false},
+		{[]string{"x"}, 0, false},
+	} {
+		v, ok := fm.LookupJoin(tc.parts, '/')
+		if v != tc.value || ok != tc.ok {
+			t.Errorf("%q: got %d, %v want %d, %v", tc.parts, v, ok, tc.value, tc.ok)
+		}
+	}
+}
+
 func checkWithMapSlice(t *testing.T, ms mapSlice) {
 	fm := faststringmap.NewUint32Store(ms)
 
@@ -42,6 +96,7 @@ func checkWithMapSlice(t *testing.T, ms mapSlice) {
 		}
 		check(fm.LookupString(k))
 		check(fm.LookupBytes([]byte(k)))
+		check(fm.Lookup2(k[:len(k)/2], k[len(k)/2:]))
 	}
 
 	for _, k := range ms.out {
@@ -52,6 +107,7 @@ func checkWithMapSlice(t *testing.T, ms mapSlice) {
 		}
 		check(fm.LookupString(k))
 		check(fm.LookupBytes([]byte(k)))
+		check(fm.Lookup2(k[:len(k)/2], k[len(k)/2:]))
 	}
 }
 
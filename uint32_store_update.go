@@ -0,0 +1,160 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import "sync/atomic"
+
+// WithKey returns a new map with key k mapped to v in addition to the
+// contents of m, which is not modified. The nodes on the path to k are
+// copied and added to the end of the store, leaving the ones they replace
+// unused. After many changes it may be worth rebuilding to reclaim the space.
+//
+// The store is one array, so nodes can only be added in place to a map
+// which has spare capacity after its store that no other map has used.
+// Otherwise the whole store is copied to a new array with room for as many
+// nodes again, taking time and memory in proportion to the size of the map.
+// So the first update of a map made by NewUint32Store or loaded from disk
+// copies the store, as does each update but the first made from the same
+// map. A sequence of updates each made from the last only copies the store
+// when the room runs out, so takes time in proportion to the length of
+// the keys rather than the size of the map, and shares the store with the
+// maps before it in the sequence.
+func (m *Uint32Store) WithKey(k string, v uint32) Uint32Store {
+	path, depth := m.path(k)
+	var bv byteValue
+	if path != nil && depth == len(k) {
+		bv = m.store[path[depth]]
+	}
+	n := m.n
+	if !bv.valid {
+		n++
+	}
+	bv.valid, bv.value = true, v
+	return m.replacePath(k, path, bv, n)
+}
+
+// WithoutKey returns a new map with the contents of m, which is not
+// modified, except for the key k. If k is not in m then m is returned.
+// As for WithKey the nodes on the path to k are copied, and those which no
+// longer lead to any key are dropped, and the whole store is also copied
+// unless m has room to add them in place.
+func (m *Uint32Store) WithoutKey(k string) Uint32Store {
+	path, depth := m.path(k)
+	if path == nil || depth < len(k) || !m.store[path[depth]].valid {
+		return *m
+	}
+	bv := m.store[path[depth]]
+	bv.valid, bv.value = false, 0
+	return m.replacePath(k, path, bv, m.n-1)
+}
+
+// replacePath returns a map with n keys made from m by replacing the node
+// for k with bv, where path is the store indexes of the nodes for the
+// prefixes of k as returned by m.path(k). Each node on the path is copied
+// with a new range of next nodes, working up to a new root.
+func (m *Uint32Store) replacePath(k string, path []uint32, bv byteValue, n int) Uint32Store {
+	base := uint32(len(m.store))
+	var add []byteValue // nodes to add to the store, starting at index base
+	for d := len(k) - 1; d >= 0; d-- {
+		var parent byteValue
+		if d < len(path) {
+			parent = m.store[path[d]]
+		}
+		bv, add = m.withNext(parent, k[d], bv, add, base)
+	}
+	root := base + uint32(len(add))
+	store, tail := m.extend(append(add, bv))
+	return Uint32Store{store: store, n: n, root: root, tail: tail}
+}
+
+// withNext returns a copy of p with c as its next node for byte b, or with
+// no next node for b if c is empty, appending its new range of next nodes
+// to add. The range is trimmed to the bytes with nodes which are not empty.
+func (m *Uint32Store) withNext(p byteValue, b byte, c byteValue, add []byteValue, base uint32) (byteValue, []byteValue) {
+	lo, hi := int(b), int(b)
+	if c.isEmpty() {
+		lo, hi = 256, -1
+	}
+	oldLo, oldHi := int(p.nextOffset), int(p.nextOffset)+int(p.nextLen)-1
+	for j := oldLo; j <= oldHi; j++ {
+		if j != int(b) && !m.store[p.nextLo+uint32(j-oldLo)].isEmpty() {
+			if j < lo {
+				lo = j
+			}
+			if j > hi {
+				hi = j
+			}
+		}
+	}
+	oldNextLo := p.nextLo
+	p.nextLo, p.nextLen, p.nextOffset = 0, 0, 0
+	if lo > hi {
+		return p, add
+	}
+	p.nextLo, p.nextLen, p.nextOffset = base+uint32(len(add)), uint16(hi-lo+1), byte(lo)
+	for j := lo; j <= hi; j++ {
+		switch {
+		case j == int(b):
+			add = append(add, c)
+		case j >= oldLo && j <= oldHi:
+			add = append(add, m.store[oldNextLo+uint32(j-oldLo)])
+		default:
+			add = append(add, byteValue{})
+		}
+	}
+	return p, add
+}
+
+// extend returns m.store with add appended, and the tail for the result.
+// The nodes are added in place if no other map made from the same store has
+// used the capacity after m.store, otherwise the store is copied to a new
+// array with room to grow, so that a sequence of updates each made from the
+// last takes time in proportion to the nodes added.
+func (m *Uint32Store) extend(add []byteValue) ([]byteValue, *uint32) {
+	n, end := len(m.store), len(m.store)+len(add)
+	if m.tail != nil && cap(m.store) >= end && atomic.CompareAndSwapUint32(m.tail, uint32(n), uint32(end)) {
+		s := m.store[:end]
+		copy(s[n:], add)
+		return s, m.tail
+	}
+	s := make([]byteValue, end, 2*end)
+	copy(s, m.store)
+	copy(s[n:], add)
+	tail := uint32(end)
+	return s, &tail
+}
+
+// rooted returns m, or a copy of m with its root first and without the
+// nodes left unused by WithKey and WithoutKey, for code which expects the
+// root to be the first node
+func (m *Uint32Store) rooted() Uint32Store {
+	if m.root == 0 {
+		return *m
+	}
+	return m.Filter(func(string, uint32) bool { return true })
+}
+
+// SetExisting sets the value of key k to v if k is in m, reporting whether
+// it was. Unlike WithKey the store is changed in place, so the change is
+// also seen by any copy of m sharing the same store, and by the maps m was
+// made from or has made by WithKey and WithoutKey which share the node for
+// k. The key set is never changed. It must not be called concurrently with
+// other use of m.
+func (m *Uint32Store) SetExisting(k string, v uint32) bool {
+	return m.UpdateExisting(k, func(p *uint32) { *p = v })
+}
+
+// UpdateExisting calls fn with a pointer to the value of key k if k is in
+// m, reporting whether it was, so that the value can be changed in place
+// as for SetExisting
+func (m *Uint32Store) UpdateExisting(k string, fn func(*uint32)) bool {
+	if len(m.store) == 0 {
+		return false
+	}
+	i, ok := m.follow(m.root, k)
+	if !ok || !m.store[i].valid {
+		return false
+	}
+	fn(&m.store[i].value)
+	return true
+}
@@ -0,0 +1,390 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestWithKeyWithoutKey(t *testing.T) {
+	m := randomSmallStrings(512, 6)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	orig := fm
+
+	want := make(map[string]uint32, len(ms.in))
+	for _, k := range ms.in {
+		want[k] = m[k]
+	}
+	all := append(append([]string(nil), ms.in...), ms.out...)
+	for i := 0; i < 1000; i++ {
+		k := all[rand.Intn(len(all))]
+		if rand.Intn(3) == 0 {
+			fm = fm.WithoutKey(k)
+			delete(want, k)
+		} else {
+			v := rand.Uint32()
+			fm = fm.WithKey(k, v)
+			want[k] = v
+		}
+	}
+	for _, k := range all {
+		wantV, wantOK := want[k]
+		if v, ok := fm.LookupString(k); v != wantV || ok != wantOK {
+			t.Errorf("%q: got %d, %v want %d, %v", k, v, ok, wantV, wantOK)
+		}
+	}
+	if fm.Len() != len(want) {
+		t.Errorf("Len got %d want %d", fm.Len(), len(want))
+	}
+	b, _ := fm.MarshalBinary()
+	var loaded faststringmap.Uint32Store
+	if err := loaded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != len(want) {
+		t.Errorf("loaded Len got %d want %d", loaded.Len(), len(want))
+	}
+	rebuilt := faststringmap.NewUint32StoreFromMap(want)
+	for name, got := range map[string]faststringmap.Uint32Store{
+		"loaded":   loaded,
+		"filtered": fm.Filter(func(string, uint32) bool { return true }),
+	} {
+		if got.Fingerprint() != rebuilt.Fingerprint() {
+			t.Errorf("%s: differs from NewUint32Store", name)
+		}
+	}
+	mn := faststringmap.MinimizeUint32Store(fm)
+	for _, k := range all {
+		wantV, wantOK := want[k]
+		if v, ok := mn.LookupString(k); v != wantV || ok != wantOK {
+			t.Errorf("minimized %q: got %d, %v want %d, %v", k, v, ok, wantV, wantOK)
+		}
+	}
+
+	// the original map must be unchanged
+	checkWithMapSlice(t, ms)
+	for _, k := range ms.in {
+		if v, ok := orig.LookupString(k); !ok || v != m[k] {
+			t.Errorf("original %q: got %d, %v want %d, true", k, v, ok, m[k])
+		}
+	}
+	for _, k := range ms.out {
+		if v, ok := orig.LookupString(k); ok {
+			t.Errorf("original %q present when not expected, got %d", k, v)
+		}
+	}
+}
+
+func TestWithKeySharesNodes(t *testing.T) {
+	m := randomSmallStrings(10000, 8)
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	nodes := fm.MemStats().Nodes
+
+	// each update copies the path to the key, up to 256 nodes per byte
+	const updates = 100
+	keys := make([]string, updates)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		fm = fm.WithKey(keys[i], uint32(i))
+	}
+	if got, max := fm.MemStats().Nodes-nodes, updates*(256*3+1); got > max {
+		t.Errorf("%d nodes added want at most %d", got, max)
+	}
+	if got, max := fm.SizeInBytes(), 4*(nodes+updates*(256*3+1))*12; got > max {
+		t.Errorf("SizeInBytes got %d want at most %d", got, max)
+	}
+
+	// maps made from the same map must not overwrite each other's nodes
+	var wg sync.WaitGroup
+	made := make([]faststringmap.Uint32Store, 8)
+	for i := range made {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			mi := fm.WithKey("made", uint32(i))
+			made[i] = mi.WithoutKey(keys[i])
+		}(i)
+	}
+	wg.Wait()
+	for i := range made {
+		if v, ok := made[i].LookupString("made"); !ok || v != uint32(i) {
+			t.Errorf("made %d: got %d, %v want %d, true", i, v, ok, i)
+		}
+		for j, k := range keys {
+			if v, ok := made[i].LookupString(k); ok != (i != j) || ok && v != uint32(j) {
+				t.Errorf("made %d: %q got %d, %v", i, k, v, ok)
+			}
+		}
+	}
+	if _, ok := fm.LookupString("made"); ok {
+		t.Error("original map changed")
+	}
+}
+
+func TestWithKeyCopiesStoreOnce(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(randomSmallStrings(10000, 8))
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	first := fm.WithKey("first", 1)
+	runtime.ReadMemStats(&after)
+	if got := after.TotalAlloc - before.TotalAlloc; got < uint64(fm.SizeInBytes()) {
+		t.Errorf("first update allocated %d bytes, less than the store of %d", got, fm.SizeInBytes())
+	}
+
+	// later updates each made from the last add to the same array
+	runtime.ReadMemStats(&before)
+	for i := 0; i < 10; i++ {
+		first = first.WithKey(strconv.Itoa(i), uint32(i))
+	}
+	runtime.ReadMemStats(&after)
+	if got := after.TotalAlloc - before.TotalAlloc; got > uint64(fm.SizeInBytes())/4 {
+		t.Errorf("10 more updates allocated %d bytes for a store of %d", got, fm.SizeInBytes())
+	}
+}
+
+func TestWithoutKeyDropsNodes(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{"abc": 1, "abd": 2, "b": 3})
+	fm = fm.WithoutKey("abc")
+	if _, ok := fm.Node("abc"); ok {
+		t.Error("node for abc still present")
+	}
+	if _, ok := fm.Node("ab"); !ok {
+		t.Error("node for ab missing")
+	}
+	fm = fm.WithoutKey("abd")
+	if _, ok := fm.Node("a"); ok {
+		t.Error("node for a still present")
+	}
+	if fm.Len() != 1 || fm.Complete("", 10)[0] != "b" {
+		t.Errorf("got %d keys %q want b", fm.Len(), fm.Complete("", 10))
+	}
+	fm = fm.WithoutKey("b")
+	if _, ok := fm.Node(""); ok || fm.Len() != 0 {
+		t.Errorf("empty map: got Len %d and a root node", fm.Len())
+	}
+}
+
+func TestWithKeyAllBytes(t *testing.T) {
+	var fm faststringmap.Uint32Store
+	for i := 255; i >= 0; i -= 2 {
+		fm = fm.WithKey(string([]byte{byte(i)}), uint32(i))
+	}
+	for i := 0; i < 256; i += 2 {
+		fm = fm.WithKey(string([]byte{byte(i)}), uint32(i))
+	}
+	if fm.Len() != 256 {
+		t.Errorf("Len got %d want 256", fm.Len())
+	}
+	for i := 0; i < 256; i++ {
+		if v, ok := fm.LookupBytes([]byte{byte(i)}); !ok || v != uint32(i) {
+			t.Errorf("%d: got %d, %v want %d, true", i, v, ok, i)
+		}
+	}
+}
+
+func TestSetExisting(t *testing.T) {
+	fm := faststringmap.NewUint32StoreFromMap(map[string]uint32{"a": 1, "ab": 2, "b": 3})
+	shared := fm
+	if !fm.SetExisting("ab", 20) {
+		t.Error("SetExisting ab returned false")
+	}
+	if !fm.UpdateExisting("b", func(v *uint32) { *v += 10 }) {
+		t.Error("UpdateExisting b returned false")
+	}
+	for _, k := range []string{"", "abc", "c", "x"} {
+		if fm.SetExisting(k, 99) || fm.UpdateExisting(k, func(*uint32) { t.Errorf("fn called for %q", k) }) {
+			t.Errorf("%q: updated when not present", k)
+		}
+	}
+	want := map[string]uint32{"a": 1, "ab": 20, "b": 13}
+	for k, wantV := range want {
+		if v, ok := shared.LookupString(k); !ok || v != wantV {
+			t.Errorf("%q: got %d, %v want %d, true", k, v, ok, wantV)
+		}
+	}
+	if fm.Len() != len(want) {
+		t.Errorf("Len got %d want %d", fm.Len(), len(want))
+	}
+
+	var zero faststringmap.Uint32Store
+	if zero.SetExisting("", 1) {
+		t.Error("SetExisting on zero map returned true")
+	}
+}
+
+func TestSetExistingAfterWithKeyWithoutKey(t *testing.T) {
+	orig := faststringmap.NewUint32StoreFromMap(map[string]uint32{"a": 1, "c": 3})
+	added := orig.WithKey("b", 2)
+	if !added.SetExisting("b", 20) {
+		t.Error("SetExisting b after WithKey returned false")
+	}
+	if !added.UpdateExisting("c", func(v *uint32) { *v += 30 }) {
+		t.Error("UpdateExisting c after WithKey returned false")
+	}
+	for k, wantV := range map[string]uint32{"a": 1, "b": 20, "c": 33} {
+		if v, ok := added.LookupString(k); !ok || v != wantV {
+			t.Errorf("added %q: got %d, %v want %d, true", k, v, ok, wantV)
+		}
+	}
+
+	removed := orig.WithoutKey("a")
+	if removed.SetExisting("a", 10) || removed.UpdateExisting("a", func(*uint32) { t.Error("fn called for a") }) {
+		t.Error("a: updated after WithoutKey")
+	}
+	if !removed.SetExisting("c", 300) {
+		t.Error("SetExisting c after WithoutKey returned false")
+	}
+	if v, ok := removed.LookupString("c"); !ok || v != 300 {
+		t.Errorf("removed c: got %d, %v want 300, true", v, ok)
+	}
+	if _, ok := removed.LookupString("a"); ok {
+		t.Error("removed a: found")
+	}
+	if v, ok := orig.LookupString("a"); !ok || v != 1 {
+		t.Errorf("orig a: got %d, %v want 1, true", v, ok)
+	}
+}
+
+// TestDerivedMapsFromRoot checks that every method which starts from the
+// root of the trie starts from m.root, which is not the first node of the
+// store for maps made by WithKey and WithoutKey
+func TestDerivedMapsFromRoot(t *testing.T) {
+	orig := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"apple": 1, "apricot": 2, "banana": 3, "cherry": 4,
+	})
+	derived := orig.WithKey("blueberry", 5)
+	derived = derived.WithoutKey("apple")
+	derived = derived.WithKey("date", 6)
+	derived = derived.WithKey("", 7)
+	rebuilt := faststringmap.NewUint32StoreFromMap(map[string]uint32{
+		"": 7, "apricot": 2, "banana": 3, "blueberry": 5, "cherry": 4, "date": 6,
+	})
+	probes := []string{"", "a", "apple", "apricot", "apricots", "b", "blueberry", "c", "cherry", "d", "date", "e"}
+	for name, f := range map[string]func(m *faststringmap.Uint32Store) interface{}{
+		"Len":  func(m *faststringmap.Uint32Store) interface{} { return m.Len() },
+		"Keys": func(m *faststringmap.Uint32Store) interface{} { return m.AppendSortedKeys(nil) },
+		"Walk": func(m *faststringmap.Uint32Store) interface{} {
+			var a []string
+			m.Walk(func(k string, v uint32) bool { a = append(a, k+"="+strconv.Itoa(int(v))); return true })
+			return a
+		},
+		"WalkDesc": func(m *faststringmap.Uint32Store) interface{} {
+			var a []string
+			m.WalkDesc(func(k string, v uint32) bool { a = append(a, k); return true })
+			return a
+		},
+		"WalkRef": func(m *faststringmap.Uint32Store) interface{} {
+			var a []string
+			m.WalkRef(func(k string, v *uint32) bool { a = append(a, k); return true })
+			return a
+		},
+		"Range": func(m *faststringmap.Uint32Store) interface{} {
+			var a []string
+			m.Range("b", "d", func(k string, v uint32) bool { a = append(a, k); return true })
+			return a
+		},
+		"Iterator": func(m *faststringmap.Uint32Store) interface{} {
+			var a []string
+			it := m.Iterator()
+			it.Seek("b")
+			for it.Next() {
+				a = append(a, it.Key())
+			}
+			return a
+		},
+		"Lookups": func(m *faststringmap.Uint32Store) interface{} {
+			var a []interface{}
+			for _, k := range probes {
+				v1, ok1 := m.LookupString(k)
+				v2, ok2 := m.LookupBytes([]byte(k))
+				v3, ok3 := m.Lookup2(k, "")
+				v4, ok4 := m.LookupJoin([]string{k}, '.')
+				v5, ok5, _ := m.LookupReader(strings.NewReader(k))
+				v6, _, ok6 := m.LookupStringFrom(m.Root(), k)
+				_, ok7 := m.Node(k)
+				a = append(a, v1, ok1, v2, ok2, v3, ok3, v4, ok4, v5, ok5, v6, ok6, ok7)
+			}
+			return a
+		},
+		"Batch": func(m *faststringmap.Uint32Store) interface{} {
+			values, found := make([]uint32, len(probes)), make([]bool, len(probes))
+			m.LookupSortedBatch(probes, values, found)
+			return []interface{}{values, found}
+		},
+		"Order": func(m *faststringmap.Uint32Store) interface{} {
+			var a []interface{}
+			k, v, ok := m.MinKey()
+			a = append(a, k, v, ok)
+			k, v, ok = m.MaxKey()
+			a = append(a, k, v, ok)
+			for _, p := range probes {
+				k, v, ok = m.NextKey(p)
+				a = append(a, k, v, ok)
+				k, v, ok = m.PrevKey(p)
+				a = append(a, k, v, ok)
+			}
+			return a
+		},
+		"Prefix": func(m *faststringmap.Uint32Store) interface{} {
+			var a []interface{}
+			for _, p := range probes {
+				v, n, ok := m.LookupLongestPrefix(p + "x")
+				a = append(a, v, n, ok, m.LookupAllPrefixes(p), m.AppendKeysWithPrefix(p, nil), m.Complete(p, 2))
+			}
+			return a
+		},
+		"KeysAfter":   func(m *faststringmap.Uint32Store) interface{} { return m.KeysAfter("apricot", 3) },
+		"Approx":      func(m *faststringmap.Uint32Store) interface{} { return m.LookupApprox("aple", 2) },
+		"Group":       func(m *faststringmap.Uint32Store) interface{} { return m.GroupByPrefix(1) },
+		"ToGoMap":     func(m *faststringmap.Uint32Store) interface{} { return m.ToGoMap() },
+		"Fingerprint": func(m *faststringmap.Uint32Store) interface{} { return m.Fingerprint() },
+		"Filter": func(m *faststringmap.Uint32Store) interface{} {
+			f := m.Filter(func(k string, _ uint32) bool { return k != "banana" })
+			return f.ToGoMap()
+		},
+		"Marshal": func(m *faststringmap.Uint32Store) interface{} {
+			b, _ := m.MarshalBinary()
+			return b
+		},
+		"Ordered": func(m *faststringmap.Uint32Store) interface{} {
+			o := faststringmap.OrderUint32Store(*m)
+			return o.CountPrefix("b")
+		},
+		"ByteRoot": func(m *faststringmap.Uint32Store) interface{} {
+			b := faststringmap.NewByteRootStore(*m)
+			v, ok := b.LookupString("blueberry")
+			return []interface{}{v, ok}
+		},
+	} {
+		if got, want := f(&derived), f(&rebuilt); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+	}
+}
+
+func BenchmarkWithKey(b *testing.B) {
+	fm := faststringmap.NewUint32StoreFromMap(randomSmallStrings(nStrsBench*10, 8))
+	b.Run("first", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			fm.WithKey("key", uint32(i))
+		}
+	})
+	b.Run("chained", func(b *testing.B) {
+		m := fm.WithKey("key", 0)
+		for i := 0; i < b.N; i++ {
+			if i%1000 == 0 {
+				m = fm.WithKey("key", 0) // bound the growth of the store
+			}
+			m = m.WithKey("key", uint32(i))
+		}
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+// walkStackSize is the depth of trie below which walks need not allocate a stack
+const walkStackSize = 32
+
+// sumFrame is a node being visited by a walk which works out a result for
+// each node from the results for its next nodes, such as the number of
+// keys below it, using an explicit stack like walkNodesFrom
+type sumFrame struct {
+	node uint32
+	next int    // next node to visit from node
+	sum  uint32 // result so far from the next nodes already visited
+}
+
+// Walk calls fn for each key in the map in sorted order until fn returns false
+func (m *Uint32Store) Walk(fn func(string, uint32) bool) {
+	m.walk(func(key []byte, v uint32) bool { return fn(string(key), v) })
+}
+
+// WalkRef calls fn for each key in the map in sorted order until fn returns
+// false, passing a pointer to the stored value. Changing the value changes
+// it in m and in any copy of m sharing the same store, as for SetExisting.
+func (m *Uint32Store) WalkRef(fn func(string, *uint32) bool) {
+	if len(m.store) == 0 {
+		return
+	}
+	m.walkRefFrom(m.root, make([]byte, 0, 256), fn)
+}
+
+func (m *Uint32Store) walkRefFrom(i uint32, key []byte, fn func(string, *uint32) bool) bool {
+	return m.walkNodesFrom(i, key, func(key []byte, bv *byteValue) bool { return fn(string(key), &bv.value) })
+}
+
+// AppendSortedKeys appends the keys in the map to a in sorted order and returns the resulting slice
+func (m *Uint32Store) AppendSortedKeys(a []string) []string {
+	m.walk(func(key []byte, _ uint32) bool {
+		a = append(a, string(key))
+		return true
+	})
+	return a
+}
+
+// WalkBytes calls fn for each key in the map in sorted order until fn
+// returns false. The key passed to fn is held in buf and is only valid for
+// the duration of the call. No allocation takes place unless a key is
+// longer than cap(buf), so reusing buf avoids creating garbage when
+// enumerating repeatedly.
+func (m *Uint32Store) WalkBytes(buf []byte, fn func(key []byte, v uint32) bool) {
+	if len(m.store) == 0 {
+		return
+	}
+	m.walkFrom(m.root, buf[:0], fn)
+}
+
+// WalkPrefixBytes is like WalkBytes but only for keys starting with prefix
+func (m *Uint32Store) WalkPrefixBytes(prefix string, buf []byte, fn func(key []byte, v uint32) bool) {
+	if len(m.store) == 0 {
+		return
+	}
+	if i, ok := m.follow(m.root, prefix); ok {
+		m.walkFrom(i, append(buf[:0], prefix...), fn)
+	}
+}
+
+// walk calls fn for each key in the map in sorted order until fn returns false.
+// The key slice passed to fn is only valid for the duration of the call.
+func (m *Uint32Store) walk(fn func(key []byte, v uint32) bool) {
+	m.WalkBytes(make([]byte, 0, 256), fn)
+}
+
+// walkFrom walks the sub-trie rooted at store index i where key is the
+// byte sequence leading to that index. It returns false if fn stopped the walk.
+func (m *Uint32Store) walkFrom(i uint32, key []byte, fn func([]byte, uint32) bool) bool {
+	return m.walkNodesFrom(i, key, func(key []byte, bv *byteValue) bool { return fn(key, bv.value) })
+}
+
+// walkNodesFrom calls fn for each valid node in the sub-trie rooted at
+// store index i in sorted order, where key is the byte sequence leading to
+// that index, until fn returns false. It uses an explicit stack, which
+// only allocates for keys of walkStackSize bytes or more, so that the
+// length of the keys does not bound the depth of the Go stack.
+func (m *Uint32Store) walkNodesFrom(i uint32, key []byte, fn func([]byte, *byteValue) bool) bool {
+	var frames [walkStackSize]iterFrame
+	stack := append(frames[:0], iterFrame{node: i, next: -1})
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		bv := &m.store[f.node]
+		switch {
+		case f.next < 0:
+			f.next = 0
+			if bv.valid && !fn(key, bv) {
+				return false
+			}
+		case f.next < int(bv.nextLen):
+			j := f.next
+			f.next++
+			stack = append(stack, iterFrame{node: bv.nextLo + uint32(j), next: -1})
+			key = append(key, bv.nextOffset+byte(j))
+		default:
+			if stack = stack[:len(stack)-1]; len(stack) > 0 {
+				key = key[:len(key)-1]
+			}
+		}
+	}
+	return true
+}
+
+// WalkDesc calls fn for each key in the map in descending order, starting
+// from the largest key, until fn returns false
+func (m *Uint32Store) WalkDesc(fn func(string, uint32) bool) {
+	if len(m.store) == 0 {
+		return
+	}
+	m.walkDescFrom(m.root, make([]byte, 0, 256), func(key []byte, v uint32) bool { return fn(string(key), v) })
+}
+
+// walkDescFrom walks the sub-trie rooted at store index i in descending
+// order where key is the byte sequence leading to that index, using an
+// explicit stack like walkNodesFrom. It returns false if fn stopped the walk.
+func (m *Uint32Store) walkDescFrom(i uint32, key []byte, fn func([]byte, uint32) bool) bool {
+	var frames [walkStackSize]iterFrame
+	// next counts down through the next nodes, then the node itself is visited
+	stack := append(frames[:0], iterFrame{node: i, next: int(m.store[i].nextLen) - 1})
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		bv := &m.store[f.node]
+		if j := f.next; j >= 0 {
+			f.next--
+			c := bv.nextLo + uint32(j)
+			stack = append(stack, iterFrame{node: c, next: int(m.store[c].nextLen) - 1})
+			key = append(key, bv.nextOffset+byte(j))
+			continue
+		}
+		if bv.valid && !fn(key, bv.value) {
+			return false
+		}
+		if stack = stack[:len(stack)-1]; len(stack) > 0 {
+			key = key[:len(key)-1]
+		}
+	}
+	return true
+}
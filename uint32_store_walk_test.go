@@ -0,0 +1,212 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"runtime/debug"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestWalkRef(t *testing.T) {
+	m := randomSmallStrings(1000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+
+	var total uint64
+	fm.WalkRef(func(k string, v *uint32) bool {
+		total += uint64(*v)
+		*v = *v*2 + 1
+		return true
+	})
+	var want uint64
+	for _, k := range ms.in {
+		want += uint64(m[k])
+		if v, ok := fm.LookupString(k); !ok || v != m[k]*2+1 {
+			t.Errorf("%q: got %d, %v want %d, true", k, v, ok, m[k]*2+1)
+		}
+	}
+	if total != want {
+		t.Errorf("total got %d want %d", total, want)
+	}
+
+	n := 0
+	fm.WalkRef(func(string, *uint32) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("WalkRef called fn %d times after returning false", n)
+	}
+}
+
+func TestWalkBytes(t *testing.T) {
+	m := randomSmallStrings(1000, 8)
+	fm := faststringmap.NewUint32StoreFromMap(m)
+	keys := fm.AppendSortedKeys(nil)
+	if len(keys) != len(m) || !sort.StringsAreSorted(keys) {
+		t.Fatalf("AppendSortedKeys gave %d keys want %d sorted", len(keys), len(m))
+	}
+
+	buf := make([]byte, 0, 16)
+	var got []string
+	fm.WalkBytes(buf, func(key []byte, v uint32) bool {
+		if v != m[string(key)] {
+			t.Errorf("%q: got %d want %d", key, v, m[string(key)])
+		}
+		got = append(got, string(key))
+		return true
+	})
+	if !equalStrings(got, keys) {
+		t.Errorf("WalkBytes gave %d keys want %d", len(got), len(keys))
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		fm.WalkPrefixBytes("a", buf, func([]byte, uint32) bool { return true })
+	})
+	if allocs != 0 {
+		t.Errorf("WalkPrefixBytes allocated %v times", allocs)
+	}
+}
+
+func TestWalkDesc(t *testing.T) {
+	m := randomSmallStrings(1000, 8)
+	ms := mapSliceN(m, len(m)/2)
+	fm := faststringmap.NewUint32Store(ms)
+	want := append([]string(nil), ms.in...)
+	sort.Sort(sort.Reverse(sort.StringSlice(want)))
+	var got []string
+	fm.WalkDesc(func(k string, v uint32) bool {
+		if v != m[k] {
+			t.Errorf("%q: got %d want %d", k, v, m[k])
+		}
+		got = append(got, k)
+		return true
+	})
+	if !equalStrings(got, want) {
+		t.Errorf("got %d keys want %d in descending order", len(got), len(want))
+	}
+
+	got = got[:0]
+	fm.WalkDesc(func(k string, _ uint32) bool {
+		got = append(got, k)
+		return len(got) < 10
+	})
+	if !equalStrings(got, want[:10]) {
+		t.Errorf("stopped walk got %q want %q", got, want[:10])
+	}
+	var zero faststringmap.Uint32Store
+	zero.WalkDesc(func(k string, _ uint32) bool {
+		t.Errorf("zero map has key %q", k)
+		return true
+	})
+}
+
+func TestWalkLongKeys(t *testing.T) {
+	long := strings.Repeat("/very/long/path", 1<<12)
+	keys := []string{"", long[:100], long, long + "a", long + "b"}
+	fm := faststringmap.NewUint32StoreFromSortedKeys(keys, func(string) uint32 { return 0 })
+	if got := fm.AppendSortedKeys(nil); !equalStrings(got, keys) {
+		t.Errorf("AppendSortedKeys got %d keys want %d", len(got), len(keys))
+	}
+	var got []string
+	fm.WalkDesc(func(k string, _ uint32) bool {
+		got = append(got, k)
+		return true
+	})
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	if !equalStrings(got, keys) {
+		t.Errorf("WalkDesc got %d keys want %d in descending order", len(got), len(keys))
+	}
+}
+
+func TestLongKeysOtherTraversals(t *testing.T) {
+	long := strings.Repeat("/very/long/path", 1<<12)
+	keys := []string{"", long[:100], long, long + "a", long + "b"}
+	fm := faststringmap.NewUint32StoreFromSortedKeys(keys, func(k string) uint32 { return uint32(len(k)) })
+
+	if k, _, _ := fm.MinKey(); k != "" {
+		t.Errorf("MinKey got %d bytes want 0", len(k))
+	}
+	if k, _, _ := fm.MaxKey(); k != long+"b" {
+		t.Errorf("MaxKey got %d bytes want %d", len(k), len(long)+1)
+	}
+	var got []string
+	fm.Range(long[:100], long+"b", func(k string, _ uint32) bool {
+		got = append(got, k)
+		return true
+	})
+	if !equalStrings(got, keys[1:4]) {
+		t.Errorf("Range got %d keys want 3", len(got))
+	}
+	if g := fm.GroupByDelimiter('x'); len(g) != len(keys) {
+		t.Errorf("GroupByDelimiter got %d groups want %d", len(g), len(keys))
+	}
+	if g := fm.GroupByPrefix(1); g["/"] != len(keys)-1 {
+		t.Errorf("GroupByPrefix got %d under / want %d", g["/"], len(keys)-1)
+	}
+	if r := fm.Report(); r.Keys != len(keys) || r.MaxDepth != len(long)+1 {
+		t.Errorf("Report got %d keys and max depth %d", r.Keys, r.MaxDepth)
+	}
+	if st := fm.MemStats(); st.WastedNodes != 0 {
+		t.Errorf("MemStats got %d wasted nodes want 0", st.WastedNodes)
+	}
+
+	odd := fm.Filter(func(k string, _ uint32) bool { return len(k)%2 == 1 })
+	u := faststringmap.Union(&odd, &fm, func(va, _ uint32) uint32 { return va })
+	mn := faststringmap.MinimizeUint32Store(fm)
+	for _, k := range keys {
+		if _, ok := odd.LookupString(k); ok != (len(k)%2 == 1) {
+			t.Errorf("Filter: %d byte key present %v", len(k), ok)
+		}
+		if v, ok := u.LookupString(k); !ok || v != uint32(len(k)) {
+			t.Errorf("Union: %d byte key got %d, %v", len(k), v, ok)
+		}
+		if v, ok := mn.LookupString(k); !ok || v != uint32(len(k)) {
+			t.Errorf("Minimize: %d byte key got %d, %v", len(k), v, ok)
+		}
+	}
+
+	o := faststringmap.OrderUint32Store(fm)
+	for i, k := range keys {
+		if r := o.Rank(k); r != i {
+			t.Errorf("Rank of %d byte key got %d want %d", len(k), r, i)
+		}
+	}
+	rc := faststringmap.NewRankedCompleter(faststringmap.Uint32MapSource(fm.ToGoMap()))
+	if c := rc.Complete(long, 1); len(c) != 1 || c[0] != long+"a" {
+		t.Errorf("RankedCompleter got %d keys", len(c))
+	}
+}
+
+func TestLongKeysOtherBackends(t *testing.T) {
+	// recursion once per byte of the long keys would overflow this stack
+	defer debug.SetMaxStack(debug.SetMaxStack(1 << 18))
+
+	// each key a prefix of the next so that RadixUint32Store has a node per byte
+	long := strings.Repeat("/very/long/path", 1<<12)
+	m := map[string]uint32{long: 0, long + "a": 1, long + "b": 2}
+	for i := 0; i < 4000; i++ {
+		m[long[:i]] = uint32(3 + i)
+	}
+	ms := mapSliceN(m, len(m))
+	ms.out = []string{"a", long[:4001], long + "c"}
+
+	adaptive := faststringmap.NewAdaptiveUint32Store(ms)
+	bitmap := faststringmap.NewBitmapUint32Store(ms)
+	louds := faststringmap.NewLOUDSUint32Store(ms)
+	radix := faststringmap.NewRadixUint32Store(ms)
+	split := faststringmap.NewSplitUint32Store(ms)
+	for name, l := range map[string]faststringmap.Lookuper{
+		"AdaptiveUint32Store": &adaptive,
+		"BitmapUint32Store":   &bitmap,
+		"LOUDSUint32Store":    &louds,
+		"RadixUint32Store":    &radix,
+		"SplitUint32Store":    &split,
+	} {
+		checkLookuper(t, name, l, ms)
+	}
+}
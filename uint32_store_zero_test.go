@@ -0,0 +1,57 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap_test
+
+import (
+	"testing"
+
+	"github.com/sensiblecodeio/faststringmap"
+)
+
+func TestZeroValue(t *testing.T) {
+	var fm faststringmap.Uint32Store
+	empty := faststringmap.NewUint32StoreFromMap(nil)
+
+	checkLookuper(t, "zero Uint32Store", &fm, mapSlice{out: []string{"", "a"}})
+	if v, ok := fm.Lookup2("", ""); ok {
+		t.Errorf("Lookup2 got %d, true", v)
+	}
+	if v, ok := fm.LookupJoin([]string{"a"}, '/'); ok {
+		t.Errorf("LookupJoin got %d, true", v)
+	}
+	if fm.Fingerprint() != empty.Fingerprint() {
+		t.Error("Fingerprint differs from empty map")
+	}
+	if len(fm.GroupByPrefix(1)) != 0 || len(fm.GroupByDelimiter('/')) != 0 {
+		t.Error("groups for zero value")
+	}
+	if err := fm.Encode([]string{"a"}, make([]uint32, 1), nil); err == nil {
+		t.Error("no error encoding with zero value")
+	}
+
+	b, _ := fm.MarshalBinary()
+	var loaded faststringmap.Uint32Store
+	if err := loaded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != 0 {
+		t.Errorf("loaded zero value has %d keys", loaded.Len())
+	}
+
+	if m := fm.WithoutKey("a"); m.Len() != 0 {
+		t.Errorf("WithoutKey gave %d keys", m.Len())
+	}
+	m := fm.WithKey("a", 1)
+	if v, ok := m.LookupString("a"); !ok || v != 1 {
+		t.Errorf("WithKey: got %d, %v want 1, true", v, ok)
+	}
+
+	var s faststringmap.Set
+	if s.Contains("") || s.Len() != 0 {
+		t.Error("zero Set not empty")
+	}
+	var skip faststringmap.SkipBytesStore
+	checkLookuper(t, "zero SkipBytesStore", &skip, mapSlice{out: []string{""}})
+	var shared faststringmap.SharedUint32Store
+	checkLookuper(t, "zero SharedUint32Store", &shared, mapSlice{out: []string{""}})
+}
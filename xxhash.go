@@ -0,0 +1,116 @@
+// Copyright 2026 The Sensible Code Company Ltd
+
+package faststringmap
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// xxHash64 primes
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxHash64 computes the 64-bit xxHash of the data written to it with seed
+// zero, implementing hash.Hash64
+type xxHash64 struct {
+	v     [4]uint64 // accumulators
+	total uint64    // bytes written
+	buf   [32]byte  // bytes not yet consumed by a stripe
+	n     int       // number of bytes in buf
+}
+
+func newXXHash64() *xxHash64 {
+	h := &xxHash64{}
+	h.Reset()
+	return h
+}
+
+func (h *xxHash64) Reset() {
+	p1, p2 := xxPrime1, xxPrime2 // variables so that the sums wrap around
+	h.v = [4]uint64{p1 + p2, p2, 0, -p1}
+	h.total, h.n = 0, 0
+}
+
+func (h *xxHash64) Size() int      { return 8 }
+func (h *xxHash64) BlockSize() int { return 32 }
+
+func (h *xxHash64) Write(b []byte) (int, error) {
+	n := len(b)
+	h.total += uint64(n)
+	if h.n+len(b) < 32 {
+		h.n += copy(h.buf[h.n:], b)
+		return n, nil
+	}
+	if h.n > 0 {
+		c := copy(h.buf[h.n:], b)
+		h.stripe(h.buf[:])
+		b = b[c:]
+		h.n = 0
+	}
+	for ; len(b) >= 32; b = b[32:] {
+		h.stripe(b)
+	}
+	h.n = copy(h.buf[:], b)
+	return n, nil
+}
+
+// stripe consumes the first 32 bytes of b
+func (h *xxHash64) stripe(b []byte) {
+	for i := range h.v {
+		h.v[i] = xxRound(h.v[i], binary.LittleEndian.Uint64(b[8*i:]))
+	}
+}
+
+func (h *xxHash64) Sum(b []byte) []byte {
+	var s [8]byte
+	binary.BigEndian.PutUint64(s[:], h.Sum64())
+	return append(b, s[:]...)
+}
+
+func (h *xxHash64) Sum64() uint64 {
+	var x uint64
+	if h.total >= 32 {
+		v := &h.v
+		x = bits.RotateLeft64(v[0], 1) + bits.RotateLeft64(v[1], 7) +
+			bits.RotateLeft64(v[2], 12) + bits.RotateLeft64(v[3], 18)
+		for _, vi := range v {
+			x = (x^xxRound(0, vi))*xxPrime1 + xxPrime4
+		}
+	} else {
+		x = xxPrime5
+	}
+	x += h.total
+
+	b := h.buf[:h.n]
+	for ; len(b) >= 8; b = b[8:] {
+		x ^= xxRound(0, binary.LittleEndian.Uint64(b))
+		x = bits.RotateLeft64(x, 27)*xxPrime1 + xxPrime4
+	}
+	if len(b) >= 4 {
+		x ^= uint64(binary.LittleEndian.Uint32(b)) * xxPrime1
+		x = bits.RotateLeft64(x, 23)*xxPrime2 + xxPrime3
+		b = b[4:]
+	}
+	for _, c := range b {
+		x ^= uint64(c) * xxPrime5
+		x = bits.RotateLeft64(x, 11) * xxPrime1
+	}
+
+	x ^= x >> 33
+	x *= xxPrime2
+	x ^= x >> 29
+	x *= xxPrime3
+	x ^= x >> 32
+	return x
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	return bits.RotateLeft64(acc, 31) * xxPrime1
+}